@@ -67,6 +67,17 @@ func (a *Action) GetContent(ctx context.Context) error {
 	return nil
 }
 
+func (a *Action) Preview(ctx context.Context, selectionString string) error {
+	res, err := a.client.Preview(ctx, selectionString)
+	if err != nil {
+		return errors.Wrap(err, "failed to get preview")
+	}
+
+	fmt.Println(res.Content)
+
+	return nil
+}
+
 func (a *Action) OpenIn(ctx context.Context, selectionString string) error {
 	log := logger.WithPrefix("action.OpenIn")
 
@@ -79,3 +90,42 @@ func (a *Action) OpenIn(ctx context.Context, selectionString string) error {
 
 	return nil
 }
+
+func (a *Action) Rename(ctx context.Context, selectionString string) error {
+	log := logger.WithPrefix("action.Rename")
+
+	err := a.client.Rename(ctx, selectionString)
+	if err != nil {
+		return errors.Wrap(err, "failed to rename")
+	}
+
+	log.Debugf("Successfully renamed selection: %s", selectionString)
+
+	return nil
+}
+
+func (a *Action) RunAction(ctx context.Context, name string, selectionString string) error {
+	log := logger.WithPrefix("action.RunAction")
+
+	err := a.client.RunAction(ctx, name, selectionString)
+	if err != nil {
+		return errors.Wrapf(err, "failed to run action %s", name)
+	}
+
+	log.Debugf("Successfully ran action %s on selection: %s", name, selectionString)
+
+	return nil
+}
+
+func (a *Action) Kill(ctx context.Context, selectionStrings []string) error {
+	log := logger.WithPrefix("action.Kill")
+
+	err := a.client.Kill(ctx, selectionStrings)
+	if err != nil {
+		return errors.Wrap(err, "failed to kill")
+	}
+
+	log.Debugf("Successfully killed selections: %v", selectionStrings)
+
+	return nil
+}
@@ -3,41 +3,55 @@ package action
 import (
 	"context"
 	"tmux-session-launcher/internal/client"
-	"tmux-session-launcher/internal/rpc"
 
 	"github.com/urfave/cli/v3"
 )
 
+// FlagSocket names the flag holding the launcher's RPC socket path.
+const FlagSocket = "socket"
+
 func HandlerNextMode(ctx context.Context, cmd *cli.Command) error {
-	c := client.NewClient(rpc.SockAddress)
+	c := client.NewClient(cmd.String(FlagSocket))
 	action := NewAction(c)
 
 	return action.NextMode(ctx)
 }
 
 func HandlerPrevMode(ctx context.Context, cmd *cli.Command) error {
-	c := client.NewClient(rpc.SockAddress)
+	c := client.NewClient(cmd.String(FlagSocket))
 	action := NewAction(c)
 
 	return action.PrevMode(ctx)
 }
 
 func HandlerGetMode(ctx context.Context, cmd *cli.Command) error {
-	c := client.NewClient(rpc.SockAddress)
+	c := client.NewClient(cmd.String(FlagSocket))
 	action := NewAction(c)
 
 	return action.GetMode(ctx)
 }
 
 func HandlerGetContent(ctx context.Context, cmd *cli.Command) error {
-	c := client.NewClient(rpc.SockAddress)
+	c := client.NewClient(cmd.String(FlagSocket))
 	action := NewAction(c)
 
 	return action.GetContent(ctx)
 }
 
+func HandlerPreview(ctx context.Context, cmd *cli.Command) error {
+	c := client.NewClient(cmd.String(FlagSocket))
+	action := NewAction(c)
+
+	args := cmd.Args().Slice()
+	if len(args) != 1 {
+		return cli.Exit("invalid number of arguments", 1)
+	}
+
+	return action.Preview(ctx, args[0])
+}
+
 func HandlerOpenIn(ctx context.Context, cmd *cli.Command) error {
-	c := client.NewClient(rpc.SockAddress)
+	c := client.NewClient(cmd.String(FlagSocket))
 	action := NewAction(c)
 
 	args := cmd.Args().Slice()
@@ -47,3 +61,40 @@ func HandlerOpenIn(ctx context.Context, cmd *cli.Command) error {
 
 	return action.OpenIn(ctx, args[0])
 }
+
+func HandlerRename(ctx context.Context, cmd *cli.Command) error {
+	c := client.NewClient(cmd.String(FlagSocket))
+	action := NewAction(c)
+
+	args := cmd.Args().Slice()
+	if len(args) != 1 {
+		return cli.Exit("invalid number of arguments", 1)
+	}
+
+	return action.Rename(ctx, args[0])
+}
+
+// HandlerRunAction runs a custom action from the configuration: <name> <selection>
+func HandlerRunAction(ctx context.Context, cmd *cli.Command) error {
+	c := client.NewClient(cmd.String(FlagSocket))
+	action := NewAction(c)
+
+	args := cmd.Args().Slice()
+	if len(args) != 2 {
+		return cli.Exit("invalid number of arguments", 1)
+	}
+
+	return action.RunAction(ctx, args[0], args[1])
+}
+
+func HandlerKill(ctx context.Context, cmd *cli.Command) error {
+	c := client.NewClient(cmd.String(FlagSocket))
+	action := NewAction(c)
+
+	args := cmd.Args().Slice()
+	if len(args) < 1 {
+		return cli.Exit("invalid number of arguments", 1)
+	}
+
+	return action.Kill(ctx, args)
+}
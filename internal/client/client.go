@@ -99,16 +99,91 @@ func (c *Client) GetContent(ctx context.Context) (*rpc.ContentResponse, error) {
 	return &result, err
 }
 
+func (c *Client) Preview(ctx context.Context, selection string) (*rpc.ContentResponse, error) {
+	category, id, err := parseSelection(selection)
+	if err != nil {
+		return nil, err
+	}
+
+	params := rpc.PreviewParams{
+		Category: category,
+		ID:       id,
+	}
+
+	var result rpc.ContentResponse
+	err = c.CallWithResult(ctx, rpc.MethodContentPreview, params, &result)
+	return &result, err
+}
+
 func (c *Client) OpenIn(ctx context.Context, selection string) error {
-	split := strings.Split(selection, "|")
-	if len(split) != 2 {
-		return errors.Errorf("invalid selection format: %s", selection)
+	category, path, err := parseSelection(selection)
+	if err != nil {
+		return err
 	}
 
 	params := rpc.OpenInParams{
-		Category: strings.TrimSpace(split[0]),
-		Path:     strings.TrimSpace(split[1]),
+		Category: category,
+		Path:     path,
 	}
 
 	return c.Call(ctx, rpc.MethodLauncherOpenIn, params, nil)
 }
+
+func (c *Client) Rename(ctx context.Context, selection string) error {
+	category, id, err := parseSelection(selection)
+	if err != nil {
+		return err
+	}
+
+	params := rpc.RenameParams{
+		Category: category,
+		ID:       id,
+	}
+
+	return c.Call(ctx, rpc.MethodLauncherRename, params, nil)
+}
+
+func (c *Client) RunAction(ctx context.Context, name string, selection string) error {
+	category, id, err := parseSelection(selection)
+	if err != nil {
+		return err
+	}
+
+	params := rpc.RunActionParams{
+		Name:     name,
+		Category: category,
+		ID:       id,
+	}
+
+	return c.Call(ctx, rpc.MethodLauncherAction, params, nil)
+}
+
+func (c *Client) Kill(ctx context.Context, selections []string) error {
+	params := rpc.KillParams{
+		Selections: make([]rpc.Selection, 0, len(selections)),
+	}
+
+	for _, selection := range selections {
+		category, id, err := parseSelection(selection)
+		if err != nil {
+			return err
+		}
+
+		params.Selections = append(params.Selections, rpc.Selection{
+			Category: category,
+			ID:       id,
+		})
+	}
+
+	return c.Call(ctx, rpc.MethodLauncherKill, params, nil)
+}
+
+// parseSelection splits a "category<TAB>id" selection as emitted by fzf's {3,4} placeholder.
+func parseSelection(selection string) (string, string, error) {
+	split := strings.Split(selection, rpc.SelectionSeparator)
+	if len(split) != 2 {
+		return "", "", errors.Errorf("invalid selection format: %s", selection)
+	}
+
+	return strings.TrimSpace(split[0]), strings.TrimSpace(split[1]), nil
+}
@@ -1,9 +1,19 @@
 package config
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"tmux-session-launcher/internal/fzf"
+	"tmux-session-launcher/pkg/glob"
+	"tmux-session-launcher/pkg/style"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,12 +21,716 @@ import (
 // Config represents the application configuration
 type Config struct {
 	Directories []DirectoryConfig `yaml:"directories"`
+	// Exclude and Include apply to every directory entry, on top of the entry's own lists
+	Exclude []string `yaml:"exclude,omitempty"`
+	Include []string `yaml:"include,omitempty"`
+	// Gitignore skips directories ignored by .gitignore files found while walking
+	Gitignore bool `yaml:"gitignore,omitempty"`
+	// Templates describe the windows and panes of newly created sessions
+	Templates []TemplateConfig `yaml:"templates,omitempty"`
+	// SessionCollision picks how a session name taken by another directory is disambiguated
+	SessionCollision string `yaml:"session_collision,omitempty"`
+	// SessionName is a text/template for session names, e.g. "{{.Parent}}-{{.Base}}"
+	SessionName string `yaml:"session_name,omitempty"`
+	// WindowName is a text/template for windows opened on a directory, same placeholders as
+	// SessionName
+	WindowName string `yaml:"window_name,omitempty"`
+	// Popup sizes and decorates the tmux popup used by --popup
+	Popup PopupConfig `yaml:"popup,omitempty"`
+	// Keys rebinds the launcher actions
+	Keys KeysConfig `yaml:"keys,omitempty"`
+	// Theme colors the picker rows and header
+	Theme ThemeConfig `yaml:"theme,omitempty"`
+	// Fzf passes extra options to the fzf instances of the launcher
+	Fzf FzfConfig `yaml:"fzf,omitempty"`
+	// Tmux selects the tmux servers to talk to
+	Tmux TmuxConfig `yaml:"tmux,omitempty"`
+	// Sources add directories tracked by other tools to the configured ones
+	Sources []SourceConfig `yaml:"sources,omitempty"`
+	// Providers add rows produced by commands, each in a mode of its own
+	Providers []ProviderConfig `yaml:"providers,omitempty"`
+	// Actions bind keys to commands run on the highlighted row
+	Actions []ActionConfig `yaml:"actions,omitempty"`
+}
+
+// ActionConfig binds a key to a command run on the highlighted row
+type ActionConfig struct {
+	// Name identifies the action in the header
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+	// Categories limits the rows the action applies to, every row when empty
+	Categories []string `yaml:"categories,omitempty"`
+	// Command is a text/template run with sh -c, e.g. "nvim {{.Path}}". .Path, .Session, .Category
+	// and .ID are already shell-quoted, so they must not be quoted again
+	Command string `yaml:"command"`
+	// Target is where the command runs: popup, new-window, split or background (the default)
+	Target string `yaml:"target,omitempty"`
+}
+
+// ProviderConfig describes a command producing picker rows. The command prints one
+// "id<TAB>name<TAB>path" line per row (tsv), name and path being optional, or a JSON array of
+// {"id", "name", "path"} objects (json).
+type ProviderConfig struct {
+	// Name is both the mode and the category of the rows
+	Name string `yaml:"name"`
+	// Command is run with sh -c
+	Command string `yaml:"command"`
+	// Format is tsv or json, defaults to tsv
+	Format string `yaml:"format,omitempty"`
+	// Action runs on select: attach, path or command, defaults to path
+	Action string `yaml:"action,omitempty"`
+	// Run is the text/template of the command action, e.g. "ssh {{.ID}}". .ID, .Name and .Path are
+	// already shell-quoted, so they must not be quoted again
+	Run string `yaml:"run,omitempty"`
+}
+
+// SourceConfig describes an external list of directories
+type SourceConfig struct {
+	// Type is zoxide or file
+	Type string `yaml:"type"`
+	// Path is the file of a file source, one directory per line
+	Path string `yaml:"path,omitempty"`
+	// Name tags the directories of the source in the picker, defaults to the type
+	Name string `yaml:"name,omitempty"`
+	// Weight scales the ranking of the source, from 0 to 1 for its best directory, against the
+	// launcher's history where an open today counts 2; defaults to 1
+	Weight float64 `yaml:"weight,omitempty"`
+}
+
+// TmuxConfig selects the tmux servers, the --tmux-server and --tmux-socket flags win over it
+type TmuxConfig struct {
+	// Server is a socket name as with tmux -L, Socket a socket path as with tmux -S
+	Server string `yaml:"server,omitempty"`
+	Socket string `yaml:"socket,omitempty"`
+	// Servers are listed next to the main one, labeled by name; an entry containing a slash is
+	// a socket path and is labeled by its base name
+	Servers []string `yaml:"servers,omitempty"`
+}
+
+// FzfConfig tweaks the fzf command line
+type FzfConfig struct {
+	// Options are appended as-is, one argument per item, e.g. "--layout=reverse" or "--height=60%"
+	Options []string `yaml:"options,omitempty"`
 }
 
 // DirectoryConfig represents a directory configuration entry
 type DirectoryConfig struct {
 	Path  string `yaml:"path"`
 	Depth int    `yaml:"depth,omitempty"`
+	// Discover switches from listing every subdirectory to listing only project roots
+	Discover string `yaml:"discover,omitempty"`
+	// Markers are extra files or directories that mark a project root when discovering
+	Markers []string `yaml:"markers,omitempty"`
+	// Exclude skips matching subdirectories entirely, Include lists only matching ones
+	Exclude   []string `yaml:"exclude,omitempty"`
+	Include   []string `yaml:"include,omitempty"`
+	Gitignore bool     `yaml:"gitignore,omitempty"`
+	// Template names the session template used for this entry and everything below it
+	Template string `yaml:"template,omitempty"`
+	// SessionName overrides the global session name template for this entry
+	SessionName string `yaml:"session_name,omitempty"`
+}
+
+// TemplateConfig describes how a new session is laid out
+type TemplateConfig struct {
+	Name string `yaml:"name"`
+	// Match applies the template to directories matching any of these globs, when no
+	// directory entry names a template explicitly
+	Match   []string          `yaml:"match,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty"`
+	Windows []WindowConfig    `yaml:"windows"`
+}
+
+// WindowConfig describes a window of a session template
+type WindowConfig struct {
+	Name string `yaml:"name,omitempty"`
+	// Path is relative to the session directory unless absolute
+	Path   string       `yaml:"path,omitempty"`
+	Layout string       `yaml:"layout,omitempty"`
+	Panes  []PaneConfig `yaml:"panes,omitempty"`
+}
+
+// PaneConfig describes a pane of a template window
+type PaneConfig struct {
+	// Command is typed into the pane's shell, so the pane stays open when it exits
+	Command string            `yaml:"command,omitempty"`
+	Path    string            `yaml:"path,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty"`
+}
+
+// PopupConfig describes the tmux popup the launcher runs in
+type PopupConfig struct {
+	// Width and Height are columns/lines or a percentage of the client, e.g. 80%
+	Width  string `yaml:"width,omitempty"`
+	Height string `yaml:"height,omitempty"`
+	// Border is a popup-border-lines style, e.g. rounded (tmux 3.3 or later)
+	Border string `yaml:"border,omitempty"`
+	Title  string `yaml:"title,omitempty"`
+}
+
+// KeysConfig maps launcher actions to fzf key names, unset actions keep their default key
+type KeysConfig struct {
+	ModeNext     string `yaml:"mode_next,omitempty"`
+	ModePrevious string `yaml:"mode_previous,omitempty"`
+	OpenIn       string `yaml:"open_in,omitempty"`
+	Preview      string `yaml:"preview,omitempty"`
+	Rename       string `yaml:"rename,omitempty"`
+	Kill         string `yaml:"kill,omitempty"`
+}
+
+// DefaultKeys are the bindings used for actions the configuration leaves unset
+var DefaultKeys = KeysConfig{
+	ModeNext:     "ctrl-j",
+	ModePrevious: "ctrl-k",
+	OpenIn:       "ctrl-o",
+	Preview:      "ctrl-/",
+	Rename:       "ctrl-r",
+	Kill:         "ctrl-x",
+}
+
+// ThemeConfig styles the picker. Colors are space separated names, 256-color numbers or #rrggbb
+// with attributes, e.g. "hi-cyan italic"; unset fields come from the preset.
+type ThemeConfig struct {
+	// Preset is the base palette, dark or light
+	Preset    string `yaml:"preset,omitempty"`
+	Session   string `yaml:"session,omitempty"`
+	Directory string `yaml:"directory,omitempty"`
+	Window    string `yaml:"window,omitempty"`
+	Pane      string `yaml:"pane,omitempty"`
+	Current   string `yaml:"current,omitempty"`
+	Path      string `yaml:"path,omitempty"`
+	Header    string `yaml:"header,omitempty"`
+	// Mute hides the search metadata of each row, so it should match the terminal background
+	Mute string `yaml:"mute,omitempty"`
+	// Fzf is passed to fzf as --color, e.g. "hl:#fabd2f,bg+:#3c3836", after the base scheme of
+	// the preset when one is set. Without either, fzf keeps its own colors.
+	Fzf string `yaml:"fzf,omitempty"`
+}
+
+const (
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+)
+
+// ThemePresets are the built-in palettes
+var ThemePresets = map[string]ThemeConfig{
+	ThemeDark: {
+		Session:   "hi-cyan italic",
+		Directory: "hi-blue italic",
+		Window:    "hi-magenta italic",
+		Pane:      "hi-yellow italic",
+		Current:   "hi-green bold",
+		Path:      "hi-black italic",
+		Header:    "faint",
+		Mute:      "#000000",
+		Fzf:       "dark",
+	},
+	ThemeLight: {
+		Session:   "cyan italic",
+		Directory: "blue italic",
+		Window:    "magenta italic",
+		Pane:      "#af5f00 italic",
+		Current:   "green bold",
+		Path:      "244 italic",
+		Header:    "faint",
+		Mute:      "#ffffff",
+		Fzf:       "light",
+	},
+}
+
+const (
+	// DiscoverGit lists directories containing .git (or any configured marker) up to Depth
+	DiscoverGit = "git"
+
+	// DefaultDiscoverDepth is used when a discovering entry does not set a depth
+	DefaultDiscoverDepth = 4
+
+	// CollisionParent prefixes parent directories until the name is free, e.g. personal/api
+	CollisionParent = "parent"
+	// CollisionSuffix appends a counter until the name is free, e.g. api-2
+	CollisionSuffix = "suffix"
+
+	// SourceZoxide reads the directories known to zoxide, ranked by their zoxide score
+	SourceZoxide = "zoxide"
+	// SourceFile reads a path-list file, every directory in it scores the source weight
+	SourceFile = "file"
+
+	ProviderFormatTSV  = "tsv"
+	ProviderFormatJSON = "json"
+
+	// ProviderActionAttach attaches to the tmux session named by the row id
+	ProviderActionAttach = "attach"
+	// ProviderActionPath opens the row path like a configured directory
+	ProviderActionPath = "path"
+	// ProviderActionCommand runs the provider's run template in the terminal of the launcher
+	ProviderActionCommand = "command"
+
+	ActionTargetPopup      = "popup"
+	ActionTargetNewWindow  = "new-window"
+	ActionTargetSplit      = "split"
+	ActionTargetBackground = "background"
+
+	DefaultPopupWidth  = "80%"
+	DefaultPopupHeight = "80%"
+)
+
+// popupBorders are the styles tmux accepts for popup-border-lines
+var popupBorders = []string{"single", "rounded", "double", "heavy", "simple", "padded", "none"}
+
+// Validate reports every configuration value that cannot be used
+func (c *Config) Validate() error {
+	var errs []error
+
+	errs = append(errs, validatePatterns("exclude", c.Exclude)...)
+	errs = append(errs, validatePatterns("include", c.Include)...)
+
+	errs = append(errs, validateNameTemplate("session_name", c.SessionName)...)
+	errs = append(errs, validateNameTemplate("window_name", c.WindowName)...)
+
+	switch c.SessionCollision {
+	case "", CollisionParent, CollisionSuffix:
+	default:
+		errs = append(errs, fmt.Errorf("unknown session_collision strategy %q", c.SessionCollision))
+	}
+
+	errs = append(errs, validatePopupSize("popup: width", c.Popup.Width)...)
+	errs = append(errs, validatePopupSize("popup: height", c.Popup.Height)...)
+
+	if c.Popup.Border != "" && !slices.Contains(popupBorders, c.Popup.Border) {
+		errs = append(errs, fmt.Errorf("popup: unknown border %q, expected one of %s",
+			c.Popup.Border, strings.Join(popupBorders, ", ")))
+	}
+
+	errs = append(errs, c.ValidateKeys()...)
+	errs = append(errs, c.validateTheme()...)
+
+	if c.Tmux.Server != "" && c.Tmux.Socket != "" {
+		errs = append(errs, fmt.Errorf("tmux: server and socket are mutually exclusive"))
+	}
+
+	labels := make(map[string]struct{})
+	for _, server := range c.Tmux.Servers {
+		label := filepath.Base(server)
+		if server == "" || strings.Contains(label, ":") {
+			errs = append(errs, fmt.Errorf("tmux: servers: %q is empty or its label contains ':'", server))
+			continue
+		}
+
+		if _, ok := labels[label]; ok {
+			errs = append(errs, fmt.Errorf("tmux: servers: more than one server labeled %q", label))
+		}
+		labels[label] = struct{}{}
+	}
+
+	sources := make(map[string]struct{})
+	for _, src := range c.Sources {
+		switch src.Type {
+		case SourceZoxide:
+		case SourceFile:
+			if src.Path == "" {
+				errs = append(errs, fmt.Errorf("sources: %s source without a path", SourceFile))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("sources: unknown type %q", src.Type))
+			continue
+		}
+
+		if src.Weight < 0 {
+			errs = append(errs, fmt.Errorf("sources: %s: weight must not be negative", src.SourceName()))
+		}
+
+		if _, ok := sources[src.SourceName()]; ok {
+			errs = append(errs, fmt.Errorf("sources: more than one source named %q", src.SourceName()))
+		}
+		sources[src.SourceName()] = struct{}{}
+	}
+
+	errs = append(errs, c.validateProviders()...)
+	errs = append(errs, c.ValidateActions()...)
+
+	for _, option := range c.Fzf.Options {
+		if fzf.IsReservedOption(option) {
+			errs = append(errs, fmt.Errorf("fzf: options: %s is managed by the launcher", option))
+		}
+	}
+
+	for _, dir := range c.Directories {
+		if dir.Path == "" {
+			errs = append(errs, fmt.Errorf("directory entry without a path"))
+			continue
+		}
+
+		if dir.Depth < 0 {
+			errs = append(errs, fmt.Errorf("directory %s: depth must not be negative", dir.Path))
+		}
+
+		if dir.Discover != "" && dir.Discover != DiscoverGit {
+			errs = append(errs, fmt.Errorf("directory %s: unknown discover mode %q", dir.Path, dir.Discover))
+		}
+
+		if len(dir.Markers) > 0 && dir.Discover == "" {
+			errs = append(errs, fmt.Errorf("directory %s: markers require discover: %s", dir.Path, DiscoverGit))
+		}
+
+		errs = append(errs, validatePatterns("directory "+dir.Path+": exclude", dir.Exclude)...)
+		errs = append(errs, validatePatterns("directory "+dir.Path+": include", dir.Include)...)
+
+		errs = append(errs, validateNameTemplate("directory "+dir.Path+": session_name", dir.SessionName)...)
+
+		if dir.Template != "" && c.FindTemplate(dir.Template) == nil {
+			errs = append(errs, fmt.Errorf("directory %s: unknown template %q", dir.Path, dir.Template))
+		}
+	}
+
+	names := make(map[string]struct{})
+	for _, tmpl := range c.Templates {
+		if tmpl.Name == "" {
+			errs = append(errs, fmt.Errorf("template without a name"))
+			continue
+		}
+
+		if _, ok := names[tmpl.Name]; ok {
+			errs = append(errs, fmt.Errorf("template %s: defined more than once", tmpl.Name))
+		}
+		names[tmpl.Name] = struct{}{}
+
+		if len(tmpl.Windows) == 0 {
+			errs = append(errs, fmt.Errorf("template %s: at least one window is required", tmpl.Name))
+		}
+
+		errs = append(errs, validatePatterns("template "+tmpl.Name+": match", tmpl.Match)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateNameTemplate(field string, text string) []error {
+	if text == "" {
+		return nil
+	}
+
+	if _, err := template.New(field).Parse(text); err != nil {
+		return []error{fmt.Errorf("%s: %w", field, err)}
+	}
+
+	return nil
+}
+
+func validatePopupSize(field string, size string) []error {
+	if size == "" {
+		return nil
+	}
+
+	n, err := strconv.Atoi(strings.TrimSuffix(size, "%"))
+	if err != nil || n <= 0 || (strings.HasSuffix(size, "%") && n > 100) {
+		return []error{fmt.Errorf("%s: expected a positive number or a percentage, got %q", field, size)}
+	}
+
+	return nil
+}
+
+// PopupOptions returns the popup settings with defaults filled in
+func (c *Config) PopupOptions() PopupConfig {
+	popup := c.Popup
+	if popup.Width == "" {
+		popup.Width = DefaultPopupWidth
+	}
+	if popup.Height == "" {
+		popup.Height = DefaultPopupHeight
+	}
+
+	return popup
+}
+
+// ValidateKeys checks the key bindings alone, fzf refuses to start on an unknown key
+func (c *Config) ValidateKeys() []error {
+	var errs []error
+
+	bindings := c.KeyBindings().list()
+	for _, action := range c.Actions {
+		bindings = append(bindings, keyBinding{"action " + action.Name, action.Key})
+	}
+
+	bound := make(map[string]string)
+	for _, binding := range bindings {
+		if !fzf.IsValidKey(binding.key) {
+			errs = append(errs, fmt.Errorf("keys: %s: unknown fzf key %q", binding.action, binding.key))
+			continue
+		}
+
+		if other, ok := bound[binding.key]; ok {
+			errs = append(errs, fmt.Errorf("keys: %s: %s is already bound to %s", binding.action, binding.key, other))
+			continue
+		}
+		bound[binding.key] = binding.action
+	}
+
+	return errs
+}
+
+func (c *Config) validateTheme() []error {
+	if _, ok := ThemePresets[cmp.Or(c.Theme.Preset, ThemeDark)]; !ok {
+		return []error{fmt.Errorf("theme: unknown preset %q, expected %s or %s", c.Theme.Preset, ThemeDark, ThemeLight)}
+	}
+
+	var errs []error
+
+	theme := c.ThemeColors()
+	for _, field := range []struct{ name, spec string }{
+		{"session", theme.Session},
+		{"directory", theme.Directory},
+		{"window", theme.Window},
+		{"pane", theme.Pane},
+		{"current", theme.Current},
+		{"path", theme.Path},
+		{"header", theme.Header},
+		{"mute", theme.Mute},
+	} {
+		if _, err := style.Parse(field.spec); err != nil {
+			errs = append(errs, fmt.Errorf("theme: %s: %w", field.name, err))
+		}
+	}
+
+	return errs
+}
+
+// ThemeColors returns the configured theme on top of its preset
+func (c *Config) ThemeColors() ThemeConfig {
+	theme := c.Theme
+	preset := ThemePresets[cmp.Or(theme.Preset, ThemeDark)]
+
+	theme.Session = cmp.Or(theme.Session, preset.Session)
+	theme.Directory = cmp.Or(theme.Directory, preset.Directory)
+	theme.Window = cmp.Or(theme.Window, preset.Window)
+	theme.Pane = cmp.Or(theme.Pane, preset.Pane)
+	theme.Current = cmp.Or(theme.Current, preset.Current)
+	theme.Path = cmp.Or(theme.Path, preset.Path)
+	theme.Header = cmp.Or(theme.Header, preset.Header)
+	theme.Mute = cmp.Or(theme.Mute, preset.Mute)
+
+	// a base scheme resets the whole fzf palette, including FZF_DEFAULT_OPTS colors, so it is
+	// only used for an explicit preset; later --color values override earlier ones
+	if c.Theme.Preset != "" && theme.Fzf != "" {
+		theme.Fzf = preset.Fzf + "," + theme.Fzf
+	} else if c.Theme.Preset != "" {
+		theme.Fzf = preset.Fzf
+	}
+
+	return theme
+}
+
+var actionNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// builtinModes are the modes, and categories, providers cannot be named after
+var builtinModes = []string{"all", "session", "directory", "window", "pane"}
+
+func (c *Config) validateProviders() []error {
+	var errs []error
+
+	names := make(map[string]struct{})
+	for _, p := range c.Providers {
+		if p.Name == "" || strings.ContainsAny(p.Name, "|:") || slices.Contains(builtinModes, p.Name) {
+			errs = append(errs, fmt.Errorf("providers: %q is not a usable name", p.Name))
+			continue
+		}
+
+		if _, ok := names[p.Name]; ok {
+			errs = append(errs, fmt.Errorf("providers: %s: defined more than once", p.Name))
+		}
+		names[p.Name] = struct{}{}
+
+		if p.Command == "" {
+			errs = append(errs, fmt.Errorf("providers: %s: command is required", p.Name))
+		}
+
+		switch p.Format {
+		case "", ProviderFormatTSV, ProviderFormatJSON:
+		default:
+			errs = append(errs, fmt.Errorf("providers: %s: unknown format %q", p.Name, p.Format))
+		}
+
+		switch p.Action {
+		case "", ProviderActionAttach, ProviderActionPath:
+		case ProviderActionCommand:
+			if p.Run == "" {
+				errs = append(errs, fmt.Errorf("providers: %s: the command action requires run", p.Name))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("providers: %s: unknown action %q", p.Name, p.Action))
+		}
+
+		errs = append(errs, validateNameTemplate("providers: "+p.Name+": run", p.Run)...)
+	}
+
+	return errs
+}
+
+// ValidateActions checks the custom actions, which the launcher turns into fzf bindings
+func (c *Config) ValidateActions() []error {
+	var errs []error
+
+	categories := slices.Clone(builtinModes[1:])
+	for _, p := range c.Providers {
+		categories = append(categories, p.Name)
+	}
+
+	names := make(map[string]struct{})
+	for _, action := range c.Actions {
+		// the name ends up on the fzf command line
+		if !actionNamePattern.MatchString(action.Name) {
+			errs = append(errs, fmt.Errorf("actions: %q is not a usable name, use letters, digits, '.', '_' and '-'", action.Name))
+			continue
+		}
+
+		if _, ok := names[action.Name]; ok {
+			errs = append(errs, fmt.Errorf("actions: %s: defined more than once", action.Name))
+		}
+		names[action.Name] = struct{}{}
+
+		for _, category := range action.Categories {
+			if !slices.Contains(categories, category) {
+				errs = append(errs, fmt.Errorf("actions: %s: unknown category %q", action.Name, category))
+			}
+		}
+
+		if action.Command == "" {
+			errs = append(errs, fmt.Errorf("actions: %s: command is required", action.Name))
+		}
+		errs = append(errs, validateNameTemplate("actions: "+action.Name+": command", action.Command)...)
+
+		switch action.Target {
+		case "", ActionTargetPopup, ActionTargetNewWindow, ActionTargetSplit, ActionTargetBackground:
+		default:
+			errs = append(errs, fmt.Errorf("actions: %s: unknown target %q", action.Name, action.Target))
+		}
+	}
+
+	return errs
+}
+
+// FindAction returns the action with the given name, or nil
+func (c *Config) FindAction(name string) *ActionConfig {
+	for i := range c.Actions {
+		if c.Actions[i].Name == name {
+			return &c.Actions[i]
+		}
+	}
+
+	return nil
+}
+
+// FindProvider returns the provider with the given name, or nil
+func (c *Config) FindProvider(name string) *ProviderConfig {
+	for i := range c.Providers {
+		if c.Providers[i].Name == name {
+			return &c.Providers[i]
+		}
+	}
+
+	return nil
+}
+
+type keyBinding struct {
+	action string
+	key    string
+}
+
+func (k KeysConfig) list() []keyBinding {
+	return []keyBinding{
+		{"mode_next", k.ModeNext},
+		{"mode_previous", k.ModePrevious},
+		{"open_in", k.OpenIn},
+		{"preview", k.Preview},
+		{"rename", k.Rename},
+		{"kill", k.Kill},
+	}
+}
+
+// KeyBindings returns the configured keys with defaults filled in
+func (c *Config) KeyBindings() KeysConfig {
+	keys := c.Keys
+	keys.ModeNext = cmp.Or(keys.ModeNext, DefaultKeys.ModeNext)
+	keys.ModePrevious = cmp.Or(keys.ModePrevious, DefaultKeys.ModePrevious)
+	keys.OpenIn = cmp.Or(keys.OpenIn, DefaultKeys.OpenIn)
+	keys.Preview = cmp.Or(keys.Preview, DefaultKeys.Preview)
+	keys.Rename = cmp.Or(keys.Rename, DefaultKeys.Rename)
+	keys.Kill = cmp.Or(keys.Kill, DefaultKeys.Kill)
+
+	return keys
+}
+
+// SourceName returns the tag of the source's directories
+func (s SourceConfig) SourceName() string {
+	return cmp.Or(s.Name, s.Type)
+}
+
+// SourceWeight returns the weight of the source with the default filled in
+func (s SourceConfig) SourceWeight() float64 {
+	if s.Weight == 0 {
+		return 1
+	}
+
+	return s.Weight
+}
+
+// DiscoverDepth returns how deep a discovering entry looks for projects, with the default filled in
+func (d DirectoryConfig) DiscoverDepth() int {
+	return cmp.Or(d.Depth, DefaultDiscoverDepth)
+}
+
+// FindTemplate returns the template with the given name, or nil
+func (c *Config) FindTemplate(name string) *TemplateConfig {
+	for i := range c.Templates {
+		if c.Templates[i].Name == name {
+			return &c.Templates[i]
+		}
+	}
+
+	return nil
+}
+
+// DirectoryFor returns the most specific directory entry containing path, or nil
+func (c *Config) DirectoryFor(path string) *DirectoryConfig {
+	var found *DirectoryConfig
+	longest := -1
+
+	for i, dir := range c.Directories {
+		root := ExpandPath(dir.Path)
+		if path != root && !strings.HasPrefix(path, root+string(filepath.Separator)) {
+			continue
+		}
+
+		if len(root) > longest {
+			found = &c.Directories[i]
+			longest = len(root)
+		}
+	}
+
+	return found
+}
+
+// ExpandPath expands environment variables and a leading ~ in a configured path
+func ExpandPath(path string) string {
+	path = os.ExpandEnv(path)
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(homeDir, path[1:])
+		}
+	}
+
+	return filepath.Clean(path)
+}
+
+func validatePatterns(field string, patterns []string) []error {
+	var errs []error
+	for _, pattern := range patterns {
+		if _, err := glob.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", field, err))
+		}
+	}
+
+	return errs
 }
 
 // defaultConfig returns the default configuration
@@ -74,11 +74,15 @@ func HandlerValidateConfig(ctx context.Context, cmd *cli.Command) error {
 	}
 
 	// Try to load config
-	_, err := Load()
+	config, err := Load()
 	if err != nil {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
 	fmt.Println("Configuration is valid")
 	return nil
 }
@@ -161,9 +165,12 @@ func HandlerListDirectories(ctx context.Context, cmd *cli.Command) error {
 
 	fmt.Println("Configured directories:")
 	for _, dir := range dirs {
-		if dir.Depth > 0 {
+		switch {
+		case dir.Discover != "":
+			fmt.Printf("  %s (discover: %s, depth: %d)\n", dir.Path, dir.Discover, dir.DiscoverDepth())
+		case dir.Depth > 0:
 			fmt.Printf("  %s (depth: %d)\n", dir.Path, dir.Depth)
-		} else {
+		default:
 			fmt.Printf("  %s\n", dir.Path)
 		}
 	}
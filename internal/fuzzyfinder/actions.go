@@ -0,0 +1,178 @@
+package fuzzyfinder
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"slices"
+	"syscall"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/internal/naming"
+	"tmux-session-launcher/internal/popup"
+	"tmux-session-launcher/internal/provider"
+	"tmux-session-launcher/internal/tmux"
+	"tmux-session-launcher/pkg/logger"
+	"tmux-session-launcher/pkg/util"
+
+	"emperror.dev/errors"
+)
+
+// actionData is what the command templates of actions can refer to
+type actionData struct {
+	Category string
+	ID       string
+	Path     string
+	// Session is the session of the row, or the one a directory would be opened in
+	Session string
+}
+
+// RunAction runs the named action from the configuration on a row.
+func RunAction(ctx context.Context, name string, category string, id string) error {
+	log := logger.WithPrefix("fuzzyfinder.RunAction")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return errors.WrapIf(err, "failed to load config")
+	}
+
+	action := cfg.FindAction(name)
+	if action == nil {
+		return errors.Errorf("unknown action: %s", name)
+	}
+
+	if len(action.Categories) > 0 && !slices.Contains(action.Categories, category) {
+		log.Debugf("Action %s does not apply to %s rows", name, category)
+		return nil
+	}
+
+	data, err := actionDataOf(ctx, cfg, category, id)
+	if err != nil {
+		return errors.WrapIff(err, "failed to resolve row: %s", id)
+	}
+
+	command, err := util.ShellTemplate(name, action.Command, data.fields())
+	if err != nil {
+		return errors.WrapIff(err, "failed to render command of action %s", name)
+	}
+
+	target := action.Target
+	if target == config.ActionTargetPopup && os.Getenv(popup.EnvInPopup) != "" {
+		// a client shows a single popup, the launcher's own is already up
+		log.Warnf("Running action %s in a new window, the launcher already runs in a popup", name)
+		target = config.ActionTargetNewWindow
+	}
+
+	log.Debugf("Running action %s (%s): %s", name, target, command)
+
+	switch target {
+	case config.ActionTargetPopup:
+		options := cfg.PopupOptions()
+
+		// the popup stays up until closed, the RPC call and fzf must not wait for it
+		go func() {
+			err := tmux.DisplayPopup(context.WithoutCancel(ctx), command, tmux.PopupOptions{
+				Width:    options.Width,
+				Height:   options.Height,
+				Border:   options.Border,
+				Title:    name,
+				Path:     data.Path,
+				KeepOpen: true,
+			})
+			if err != nil {
+				log.Errorf("Action %s failed: %v", name, err)
+			}
+		}()
+
+		return nil
+	case config.ActionTargetNewWindow:
+		_, _, err := tmux.WindowCreateWithOptions(ctx, tmux.WindowOptions{
+			Name:    name,
+			Path:    data.Path,
+			Command: command,
+		})
+		return err
+	case config.ActionTargetSplit:
+		_, err := tmux.PaneCreateWithOptions(ctx, tmux.PaneOptions{
+			Path:    data.Path,
+			Command: command,
+		})
+		return err
+	default:
+		return runBackground(command, data.Path)
+	}
+}
+
+// fields returns the template placeholders of the data, paths and provider IDs are arbitrary
+// text that util.ShellTemplate quotes
+func (d actionData) fields() map[string]string {
+	return map[string]string{
+		"Category": d.Category,
+		"ID":       d.ID,
+		"Path":     d.Path,
+		"Session":  d.Session,
+	}
+}
+
+func actionDataOf(ctx context.Context, cfg *config.Config, category string, id string) (actionData, error) {
+	data := actionData{Category: category, ID: id}
+
+	switch category {
+	case categoryDirectory:
+		data.Path = id
+		// without a collision check, the session may still get a suffix when opened
+		data.Session, _ = naming.Render(cfg, id)
+
+	case categorySession, categoryWindow, categoryPane:
+		sctx, tmuxID, err := serverContext(ctx, category, id)
+		if err != nil {
+			return data, err
+		}
+
+		session, err := tmux.GetSession(sctx, tmuxID)
+		if err != nil {
+			return data, err
+		}
+		data.Session = session.Name
+
+		if category == categorySession {
+			data.Path, err = tmux.SessionRootPath(sctx, session.Name)
+		} else {
+			data.Path, err = tmux.PanePath(sctx, tmuxID)
+		}
+		if err != nil {
+			return data, err
+		}
+
+	default:
+		p := findProvider(category)
+		if p == nil {
+			return data, errors.Errorf("invalid category: %s", category)
+		}
+
+		row, err := provider.Find(ctx, *p, id)
+		if err != nil {
+			return data, err
+		}
+		data.Path = row.Path
+	}
+
+	return data, nil
+}
+
+// runBackground starts the command detached from the launcher, so it survives it
+func runBackground(command string, path string) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		cmd.Dir = path
+	}
+
+	if err := cmd.Start(); err != nil {
+		return errors.WrapIf(err, "failed to start action")
+	}
+
+	// reap it whenever it exits
+	go cmd.Wait()
+
+	return nil
+}
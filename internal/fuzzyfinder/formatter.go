@@ -3,22 +3,37 @@ package fuzzyfinder
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/internal/history"
 	"tmux-session-launcher/internal/mode"
 	"tmux-session-launcher/internal/tmux"
 	"tmux-session-launcher/internal/workspace"
 	"tmux-session-launcher/pkg/logger"
+	"tmux-session-launcher/pkg/util"
 	"unicode/utf8"
 
 	"github.com/acarl005/stripansi"
-	"github.com/fatih/color"
 	"github.com/rodaine/table"
 )
 
-func buildHeader() string {
-	c := color.New(color.Faint, color.Bold, color.Italic)
+func buildHeader(cfg *config.Config) string {
+	keys := cfg.KeyBindings()
+	header := colorHeader(fmt.Sprintf(
+		"Press %s/%s to switch mode, %s to open in, %s to toggle preview, %s to rename, %s to kill",
+		keys.ModeNext, keys.ModePrevious, keys.OpenIn, keys.Preview, keys.Rename, keys.Kill,
+	)) + "\n"
+
+	if len(cfg.Actions) > 0 {
+		aSlc := make([]string, 0, len(cfg.Actions))
+		for _, action := range cfg.Actions {
+			aSlc = append(aSlc, fmt.Sprintf("%s %s", action.Key, action.Name))
+		}
 
-	header := color.New(color.Faint).Sprintf("Press %s/%s to switch mode\n", keyModeNext, keyModePrev)
+		header += colorHeader("Actions: "+strings.Join(aSlc, ", ")) + "\n"
+	}
 
 	currentMode := mode.Get()
 
@@ -29,7 +44,7 @@ func buildHeader() string {
 			continue
 		}
 
-		mSlc = append(mSlc, c.Sprint(m))
+		mSlc = append(mSlc, colorHeader(m))
 	}
 
 	header += strings.Join(mSlc, " ")
@@ -37,34 +52,108 @@ func buildHeader() string {
 	return header
 }
 
+// Entry is a single row of the picker, independent of how it is rendered.
+type Entry struct {
+	Category string            `json:"category"`
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Path     string            `json:"path"`
+	Current  bool              `json:"current"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
 func buildContent(ctx context.Context) (string, error) {
-	currentMode := mode.Get()
+	entries := buildEntries(ctx, mode.Get())
 
-	output := make([][]string, 0)
+	return formatTable(formatEntriesAsRows(entries, fzfSeparator)), nil
+}
 
-	if currentMode == mode.ModeSession || currentMode == mode.ModeAll {
-		sessions, err := tmux.GetSessions(ctx)
-		if err != nil {
-			logger.Warnf("Failed to get tmux sessions: %v", err)
-		}
+// GetEntries returns the rows of the given mode in the order the picker shows them.
+func GetEntries(ctx context.Context, m mode.Mode) []Entry {
+	return buildEntries(ctx, m)
+}
 
-		formattedSessions := formatEntryTmuxSessionsAsRows(sessions, fzfSeparator)
-		output = append(output, formattedSessions...)
+func buildEntries(ctx context.Context, currentMode mode.Mode) []Entry {
+	entries := make([]Entry, 0)
+
+	hist, err := history.Load()
+	if err != nil {
+		logger.Warnf("Failed to load history: %v", err)
+		hist = &history.History{}
+	}
+	now := time.Now()
+
+	if currentMode == mode.ModeSession || currentMode == mode.ModeAll {
+		entries = append(entries, fromServers(ctx, func(ctx context.Context) []Entry {
+			sessions, err := tmux.GetSessions(ctx)
+			if err != nil {
+				logger.Warnf("Failed to get tmux sessions: %v", err)
+			}
+
+			// the current session stays on top, the rest by frecency
+			sort.SliceStable(sessions, func(i, j int) bool {
+				if sessions[i].Current != sessions[j].Current {
+					return sessions[i].Current
+				}
+
+				return hist.Score(history.KindSession, sessions[i].Name, now) >
+					hist.Score(history.KindSession, sessions[j].Name, now)
+			})
+
+			return sessionEntries(sessions)
+		})...)
 	}
 
 	if currentMode == mode.ModeDirectory || currentMode == mode.ModeAll {
-		dirs := workspace.GetDirectories()
+		dirs := workspace.GetDirectories(ctx)
+
+		// our own history plus the ranking of the source the directory came from
+		sort.SliceStable(dirs, func(i, j int) bool {
+			return hist.Score(history.KindDirectory, dirs[i].FullPath, now)+dirs[i].Score >
+				hist.Score(history.KindDirectory, dirs[j].FullPath, now)+dirs[j].Score
+		})
 
-		formattedDirs := formatEntryDirectoryAsRows(dirs, fzfSeparator)
-		output = append(output, formattedDirs...)
+		entries = append(entries, directoryEntries(dirs)...)
 	}
 
-	return formatTable(output), nil
+	if currentMode == mode.ModeWindow {
+		entries = append(entries, fromServers(ctx, func(ctx context.Context) []Entry {
+			windows, err := tmux.GetWindows(ctx)
+			if err != nil {
+				logger.Warnf("Failed to get tmux windows: %v", err)
+			}
+
+			return windowEntries(windows)
+		})...)
+	}
+
+	if currentMode == mode.ModePane {
+		entries = append(entries, fromServers(ctx, func(ctx context.Context) []Entry {
+			panes, err := tmux.GetPanes(ctx)
+			if err != nil {
+				logger.Warnf("Failed to get tmux panes: %v", err)
+			}
+
+			return paneEntries(panes)
+		})...)
+	}
+
+	entries = append(entries, providerEntries(ctx, currentMode)...)
+
+	return entries
 }
 
 func formatTable(rows [][]string) string {
+	if noColor() {
+		for _, row := range rows {
+			for i := range row {
+				row[i] = stripansi.Strip(row[i])
+			}
+		}
+	}
+
 	var output strings.Builder
-	// fzf metadata: display|searchable|type|id
+	// fzf metadata, separated by fzfSeparator: display, searchable, type, id
 	tbl := table.
 		New("category", "name", "path", "searchable", "type+id").
 		WithWriter(&output).
@@ -79,41 +168,116 @@ func formatTable(rows [][]string) string {
 	return output.String()
 }
 
-func formatEntryTmuxSessionsAsRows(sessions []tmux.Session, fzfSep string) [][]string {
-	rows := make([][]string, 0)
+func sessionEntries(sessions []tmux.Session) []Entry {
+	entries := make([]Entry, 0, len(sessions))
 
 	for _, s := range sessions {
-		sessionName := s.Name
-		if s.Current {
-			sessionName = fmt.Sprintf("[%s]", colorCurrentSession(sessionName))
-		} else {
-			sessionName = colorDefault(sessionName)
-		}
+		entries = append(entries, Entry{
+			Category: categorySession,
+			ID:       s.ID,
+			Name:     s.Name,
+			Path:     util.ExpandHomePath(s.Path),
+			Current:  s.Current,
+		})
+	}
 
-		cols := make([]string, 0)
-		cols = append(cols, colorCategorySession(categorySession))
-		cols = append(cols, sessionName)
-		cols = append(cols, colorPath(s.Path))
-		cols = append(cols, colorMute(fzfSep, s.Name))
-		cols = append(cols, colorMute(fzfSep+categorySession+fzfSep+s.ID))
+	return entries
+}
 
-		rows = append(rows, cols)
+func windowEntries(windows []tmux.Window) []Entry {
+	entries := make([]Entry, 0, len(windows))
+
+	for _, w := range windows {
+		entries = append(entries, Entry{
+			Category: categoryWindow,
+			ID:       w.ID,
+			Name:     fmt.Sprintf("%s:%s %s", w.SessionName, w.Index, w.Name),
+			Path:     util.ExpandHomePath(w.Path),
+			Metadata: map[string]string{
+				"session":    w.SessionName,
+				"session_id": w.SessionID,
+				"index":      w.Index,
+				"window":     w.Name,
+			},
+		})
 	}
 
-	return rows
+	return entries
+}
+
+func paneEntries(panes []tmux.Pane) []Entry {
+	entries := make([]Entry, 0, len(panes))
+
+	for _, p := range panes {
+		entries = append(entries, Entry{
+			Category: categoryPane,
+			ID:       p.ID,
+			Name:     fmt.Sprintf("%s:%s %s", p.SessionName, p.Index, p.Command),
+			Path:     util.ExpandHomePath(p.Path),
+			Metadata: map[string]string{
+				"session":    p.SessionName,
+				"session_id": p.SessionID,
+				"index":      p.Index,
+				"command":    p.Command,
+			},
+		})
+	}
+
+	return entries
 }
 
-func formatEntryDirectoryAsRows(dirs []workspace.Directory, fzfSep string) [][]string {
-	rows := make([][]string, 0)
+func directoryEntries(dirs []workspace.Directory) []Entry {
+	entries := make([]Entry, 0, len(dirs))
 
 	for _, d := range dirs {
-		cols := make([]string, 0)
+		entries = append(entries, Entry{
+			Category: categoryDirectory,
+			ID:       d.FullPath,
+			Name:     d.Label,
+			Path:     d.FullPath,
+			Metadata: map[string]string{
+				"parent": d.Parent,
+				"source": d.Source,
+			},
+		})
+	}
+
+	return entries
+}
 
-		cols = append(cols, colorCategoryDir(categoryDirectory))
-		cols = append(cols, d.Label)
-		cols = append(cols, colorPath(d.TruncatedHomePath))
-		cols = append(cols, colorMute(fzfSep, d.Label))
-		cols = append(cols, colorMute(fzfSep+categoryDirectory+fzfSep+d.FullPath))
+func formatEntriesAsRows(entries []Entry, fzfSep string) [][]string {
+	rows := make([][]string, 0, len(entries))
+
+	// free text must not add fields to the row
+	text := strings.NewReplacer(fzfSep, " ", "\n", " ").Replace
+
+	for _, e := range entries {
+		if strings.Contains(e.ID, fzfSep) || strings.Contains(e.Category, fzfSep) {
+			// the id is handed back as is, it cannot be replaced
+			logger.Warnf("Skipping %s row with %q in its id: %s", e.Category, fzfSep, e.ID)
+			continue
+		}
+
+		name := colorDefault(text(e.Name))
+		if e.Current {
+			name = fmt.Sprintf("[%s]", colorCurrentSession(text(e.Name)))
+		}
+
+		if server := e.Metadata["server"]; server != "" {
+			name = colorPath(text(server)+serverSeparator) + name
+		}
+
+		category := e.Category
+		if source := e.Metadata["source"]; source != "" && source != workspace.ConfigSource {
+			category += ":" + text(source)
+		}
+
+		cols := make([]string, 0)
+		cols = append(cols, categoryColor(e.Category)(category))
+		cols = append(cols, name)
+		cols = append(cols, colorPath(text(util.TruncateHomePath(e.Path))))
+		cols = append(cols, colorMute(fzfSep, text(e.Name)))
+		cols = append(cols, colorMute(fzfSep+e.Category+fzfSep+e.ID))
 
 		rows = append(rows, cols)
 	}
@@ -121,8 +285,26 @@ func formatEntryDirectoryAsRows(dirs []workspace.Directory, fzfSep string) [][]s
 	return rows
 }
 
+func categoryColor(category string) func(a ...any) string {
+	switch category {
+	case categorySession:
+		return colorCategorySession
+	case categoryDirectory:
+		return colorCategoryDir
+	case categoryWindow:
+		return colorCategoryWindow
+	case categoryPane:
+		return colorCategoryPane
+	default:
+		return colorDefault
+	}
+}
+
 func parseSelectedOutput(output string, fzfSep string) (string, string, error) {
-	parts := strings.Split(strings.TrimSpace(output), fzfSep)
+	// with --multi every selected row is printed, only the first one is opened
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+
+	parts := strings.Split(strings.TrimSpace(line), fzfSep)
 	if len(parts) != 2 {
 		return "", "", fmt.Errorf("unexpected output format: %s", output)
 	}
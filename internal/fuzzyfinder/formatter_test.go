@@ -0,0 +1,43 @@
+package fuzzyfinder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatEntriesAsRowsSeparator(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	entries := []Entry{
+		{Category: categoryWindow, ID: "@3", Name: "logs|tail", Path: "/srv/a|b"},
+		{Category: categorySession, ID: "$1", Name: "tab\there", Path: "/srv/tab\tdir", Current: true},
+		{Category: "ssh", ID: "web|1", Name: "web", Metadata: map[string]string{"source": "hosts|prod"}},
+		{Category: "ssh", ID: "web\t2", Name: "web"},
+	}
+
+	lines := strings.Split(strings.TrimRight(formatTable(formatEntriesAsRows(entries, fzfSeparator)), "\n"), "\n")
+	want := [][2]string{
+		{categoryWindow, "@3"},
+		{categorySession, "$1"},
+		{"ssh", "web|1"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d rows, want %d:\n%s", len(lines), len(want), strings.Join(lines, "\n"))
+	}
+
+	for i, line := range lines {
+		fields := strings.Split(line, fzfSeparator)
+		if len(fields) != 4 {
+			t.Fatalf("row %d has %d fields, want 4: %q", i, len(fields), line)
+		}
+
+		// what fzf prints for --accept-nth 3,4
+		category, id, err := parseSelectedOutput(fields[2]+fzfSeparator+fields[3], fzfSeparator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if category != want[i][0] || strings.TrimSpace(id) != want[i][1] {
+			t.Errorf("row %d selects %q %q, want %q %q", i, category, id, want[i][0], want[i][1])
+		}
+	}
+}
@@ -2,41 +2,46 @@ package fuzzyfinder
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"tmux-session-launcher/internal/config"
 	"tmux-session-launcher/internal/fzf"
+	"tmux-session-launcher/internal/naming"
+	"tmux-session-launcher/internal/rpc"
+	"tmux-session-launcher/internal/sessiontemplate"
 	"tmux-session-launcher/internal/tmux"
 	"tmux-session-launcher/pkg/logger"
-
-	"github.com/fatih/color"
+	"tmux-session-launcher/pkg/util"
 
 	"emperror.dev/errors"
 )
 
 const (
-	fzfPort      = 6266
-	fzfSeparator = "|"
+	fzfSeparator = rpc.SelectionSeparator
 
 	categorySession   = "session"
 	categoryDirectory = "directory"
-
-	keyModeNext = "ctrl-j"
-	keyModePrev = "ctrl-k"
-	keyOpenIn   = "ctrl-o"
+	categoryWindow    = "window"
+	categoryPane      = "pane"
 )
 
-var (
-	colorDefault         = fmt.Sprint
-	colorCategorySession = color.New(color.FgHiCyan, color.Italic).Sprint
-	colorCategoryDir     = color.New(color.FgHiBlue, color.Italic).Sprint
-	colorCurrentSession  = color.New(color.FgHiGreen, color.Bold).Sprint
-	colorPath            = color.New(color.FgHiBlack, color.Italic).Sprint
-	colorMute            = color.RGB(0, 0, 0).Sprint
-)
+// Selection is a single picked row, identified by its category and id metadata.
+type Selection struct {
+	Category string
+	ID       string
+}
 
-func Launcher(ctx context.Context) error {
+// LauncherOptions tweaks how the picker starts.
+type LauncherOptions struct {
+	// Query is typed into the prompt on start
+	Query string
+}
+
+func Launcher(ctx context.Context, listener *fzf.Listener, opts LauncherOptions) error {
 	log := logger.WithPrefix("fuzzyfinder.Exec")
 
 	execPath, err := os.Executable()
@@ -44,19 +49,68 @@ func Launcher(ctx context.Context) error {
 		return errors.WrapIf(err, "failed to get executable path")
 	}
 
+	cfg, err := config.Load()
+	if err != nil {
+		return errors.WrapIf(err, "failed to load config")
+	}
+
+	// fzf would only report an opaque error for the whole command line
+	if errs := cfg.ValidateKeys(); len(errs) > 0 {
+		return errors.WrapIf(errors.Combine(errs...), "invalid key bindings in config")
+	}
+
+	// action names and keys end up in the fzf command line
+	if errs := cfg.ValidateActions(); len(errs) > 0 {
+		return errors.WrapIf(errors.Combine(errs...), "invalid actions in config")
+	}
+
+	keys := cfg.KeyBindings()
+	theme := cfg.ThemeColors()
+	setTheme(theme)
+
+	// the muted name column is only hidden while colors are on, so search the display column instead
+	withNth, nth := "1,2", "2"
+	if noColor() {
+		withNth, nth = "1", "1"
+	}
+
 	args := []string{
 		"--ansi",
 		"--no-sort",
 		"--no-hscroll",
-		"--listen", fmt.Sprint(fzfPort),
-		"--header", buildHeader(),
+		"--multi",
+		"--header", buildHeader(cfg),
 		"--delimiter", fzfSeparator, // used as nth delimiter
-		"--with-nth", "1,2", // what to show in the list
-		"--nth", "2", // what to search in (based on with-nth)
+		"--with-nth", withNth, // what to show in the list
+		"--nth", nth, // what to search in (based on with-nth)
 		"--accept-nth", "3,4", // what to output on accept
-		fmt.Sprintf("--bind=%s:execute-silent(%s action mode-next)", keyModeNext, execPath),
-		fmt.Sprintf("--bind=%s:execute-silent(%s action mode-previous)", keyModePrev, execPath),
-		fmt.Sprintf("--bind=%s:become(%s action open-in {3,4})", keyOpenIn, execPath),
+		fmt.Sprintf("--bind=%s:execute-silent(%s action mode-next)", keys.ModeNext, execPath),
+		fmt.Sprintf("--bind=%s:execute-silent(%s action mode-previous)", keys.ModePrevious, execPath),
+		fmt.Sprintf("--bind=%s:become(%s action open-in {3,4})", keys.OpenIn, execPath),
+		fmt.Sprintf("--bind=%s:toggle-preview", keys.Preview),
+		fmt.Sprintf("--bind=%s:execute(%s action rename {3,4})", keys.Rename, execPath),
+		fmt.Sprintf("--bind=%s:execute(%s action kill {+3,4})+clear-selection", keys.Kill, execPath),
+		"--preview", fmt.Sprintf("%s action preview {3,4}", execPath),
+		"--preview-window", "right,50%,border-left",
+	}
+
+	for _, action := range cfg.Actions {
+		args = append(args, fmt.Sprintf("--bind=%s:execute-silent(%s action run %s {3,4})", action.Key, execPath, util.ShellQuote(action.Name)))
+	}
+
+	if theme.Fzf != "" && !noColor() {
+		args = append(args, "--color", theme.Fzf)
+	}
+
+	if opts.Query != "" {
+		args = append(args, "--query", opts.Query)
+	}
+
+	args = append(args, userOptions(cfg)...)
+
+	// open-in moves windows and panes to the pane the user came from, not to the picker
+	if err := recordOrigin(ctx); err != nil {
+		log.Debugf("Failed to record the origin pane: %v", err)
 	}
 
 	input, err := buildContent(ctx)
@@ -67,8 +121,9 @@ func Launcher(ctx context.Context) error {
 	outputBuf := &bytes.Buffer{}
 	errBuf := &bytes.Buffer{}
 
-	if err := fzf.Select(
+	if err := fzf.SelectListening(
 		ctx,
+		listener,
 		args,
 		bytes.NewReader([]byte(input)),
 		outputBuf,
@@ -94,6 +149,18 @@ func Launcher(ctx context.Context) error {
 		return errors.WrapIf(err, "failed to parse fzf output")
 	}
 
+	return Open(ctx, category, id)
+}
+
+// Open switches to the entry the same way accepting it in the picker does.
+func Open(ctx context.Context, category string, id string) error {
+	log := logger.WithPrefix("fuzzyfinder.Open")
+
+	ctx, id, err := serverContext(ctx, category, id)
+	if err != nil {
+		return err
+	}
+
 	var errTmux error
 	switch category {
 	case categorySession:
@@ -102,7 +169,18 @@ func Launcher(ctx context.Context) error {
 
 	case categoryDirectory:
 		log.Infof("Opening directory with path: %s", id)
-		_, errTmux = tmux.SessionCreateOrAttach(ctx, tmux.BuildSessionNameFromPath(id), id)
+		errTmux = openDirectory(ctx, id)
+
+	case categoryWindow:
+		log.Infof("Selecting tmux window with ID: %s", id)
+		errTmux = tmux.WindowSelect(ctx, id)
+
+	case categoryPane:
+		log.Infof("Selecting tmux pane with ID: %s", id)
+		errTmux = tmux.PaneSelect(ctx, id)
+
+	default:
+		return openProvider(ctx, category, id)
 	}
 
 	if errTmux != nil {
@@ -112,10 +190,35 @@ func Launcher(ctx context.Context) error {
 	return nil
 }
 
-func UpdateContentAndHeader(ctx context.Context) error {
-	header := buildHeader()
+// openDirectory attaches to the directory's session, creating it from its template if needed.
+func openDirectory(ctx context.Context, path string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return errors.WrapIf(err, "failed to load configuration")
+	}
+
+	name, err := naming.SessionName(ctx, cfg, path)
+	if err != nil {
+		return errors.WrapIff(err, "failed to name session for: %s", path)
+	}
+
+	// attaching may replace the process, so it is recorded first
+	recordDirectory(path)
+
+	_, err = tmux.SessionCreateOrAttach(ctx, name, path, sessiontemplate.Setup(path))
+
+	return err
+}
+
+func UpdateContentAndHeader(ctx context.Context, listener *fzf.Listener) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return errors.WrapIf(err, "failed to load config")
+	}
+
+	header := buildHeader(cfg)
 
-	if err := fzf.UpdateContentAndHeader(ctx, fzfPort, header); err != nil {
+	if err := fzf.UpdateContentAndHeader(ctx, listener, header); err != nil {
 		return errors.WrapIf(err, "failed to update fzf content and header")
 	}
 
@@ -131,55 +234,210 @@ func GetContent(ctx context.Context) (string, error) {
 	return content, nil
 }
 
-func OpenIn(ctx context.Context, category string, path string) error {
-	if category == "session" {
-		return tmux.SessionAttach(ctx, path)
-	}
+func OpenIn(ctx context.Context, category string, id string) error {
+	targets := directoryTargets
+
+	switch category {
+	case categoryDirectory:
+	case categorySession:
+		// windows and panes cannot move between tmux servers
+		if strings.Contains(id, serverSeparator) {
+			return Open(ctx, category, id)
+		}
 
-	if category != "directory" {
+		targets = sessionTargets(ctx, id)
+	case categoryWindow, categoryPane:
+		return Open(ctx, category, id)
+	default:
 		return fmt.Errorf("invalid category: %s", category)
 	}
 
 	log := logger.WithPrefix("fuzzyfinder.OpenIn")
-	input := []string{
-		"pane",
-		"window",
-		"session",
+
+	cfg, err := config.Load()
+	if err != nil {
+		return errors.WrapIf(err, "failed to load config")
 	}
 
-	binds := make([]string, 0)
-	for _, option := range input {
-		binds = append(binds, string(option[0]))
+	target, err := selectOpenInTarget(ctx, cfg, targets)
+	if err != nil || target == nil {
+		return err
 	}
 
-	args := []string{
-		"--multi",
-		"--bind", "result:jump,jump:accept",
-		"--jump-labels", strings.Join(binds, ""),
+	log.Debugf("Opening %s %s in %s", category, id, target.Name)
+
+	return target.Open(ctx, cfg, id)
+}
+
+// userOptions returns the configured and FZF_LAUNCHER_OPTS options, without those the launcher
+// relies on. They go last so they win over the launcher defaults.
+func userOptions(cfg *config.Config) []string {
+	log := logger.WithPrefix("fuzzyfinder.userOptions")
+
+	options := slices.Clone(cfg.Fzf.Options)
+
+	if env := os.Getenv(fzf.EnvLauncherOptions); env != "" {
+		words, err := util.ShellSplit(env)
+		if err != nil {
+			log.Warnf("Ignoring %s: %v", fzf.EnvLauncherOptions, err)
+		} else {
+			options = append(options, words...)
+		}
+	}
+
+	options, dropped := fzf.FilterOptions(options)
+	for _, name := range dropped {
+		log.Warnf("Ignoring fzf option %s, it is managed by the launcher", name)
+	}
+
+	return options
+}
+
+// Rename prompts for a new name for the session until tmux accepts it or the user gives up.
+func Rename(ctx context.Context, listener *fzf.Listener, category string, id string) error {
+	if category != categorySession {
+		return fmt.Errorf("only sessions can be renamed, got: %s", category)
 	}
 
-	output, errOutput, err := fzf.SelectWithString(ctx, args, strings.Join(input, "\n"))
+	log := logger.WithPrefix("fuzzyfinder.Rename")
+
+	// ctx itself keeps listing the main server on reload
+	sctx, id, err := serverContext(ctx, category, id)
 	if err != nil {
-		if errors.Is(err, fzf.ErrUserCancelled) {
+		return err
+	}
+
+	session, err := tmux.GetSession(sctx, id)
+	if err != nil {
+		return errors.WrapIff(err, "failed to get session: %s", id)
+	}
+
+	header := ""
+	for {
+		name, err := fzf.Prompt(ctx, "Rename session> ", session.Name, header)
+		if err != nil {
+			if errors.Is(err, fzf.ErrUserCancelled) {
+				return nil
+			}
+
+			return errors.WrapIf(err, "fzf prompt failed")
+		}
+
+		if name == session.Name {
 			return nil
 		}
 
-		log.Errorf("fzf selection failed: %v", errOutput)
-		return errors.WrapIf(err, "fzf selection failed")
+		err = tmux.SessionRename(sctx, id, name)
+		if errors.Is(err, tmux.ErrSessionExists) || errors.Is(err, tmux.ErrInvalidSessionName) {
+			header = fmt.Sprintf("%s: %s", name, err)
+			continue
+		}
+
+		if err != nil {
+			return errors.WrapIff(err, "failed to rename session: %s", session.Name)
+		}
+
+		log.Infof("Renamed session %s to %s", session.Name, name)
+		break
 	}
 
-	log.Debugf("fzf output: %s | path: %s", output, path)
+	return UpdateContentAndHeader(ctx, listener)
+}
+
+// Kill kills every selected session after confirmation, moving the client away from the
+// current session first so it is not detached.
+func Kill(ctx context.Context, listener *fzf.Listener, selections []Selection) error {
+	log := logger.WithPrefix("fuzzyfinder.Kill")
 
-	switch output {
-	case "pane":
-		err = tmux.PaneCreate(ctx, path)
-	case "window":
-		err = tmux.WindowCreate(ctx, path)
-	case "session":
-		_, err = tmux.SessionCreateOrAttach(ctx, tmux.BuildSessionNameFromPath(path), path)
-	default:
-		err = errors.New("invalid input")
+	sessions, err := tmux.GetSessions(ctx)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get tmux sessions")
 	}
 
-	return err
+	for _, server := range extraServers() {
+		others, err := tmux.GetSessions(tmux.WithServer(ctx, server))
+		if err != nil {
+			log.Warnf("Failed to get tmux sessions of %s: %v", server.Label(), err)
+			continue
+		}
+
+		// ids as listed by the picker, see fromServers
+		for _, s := range others {
+			s.ID = server.Label() + serverSeparator + s.ID
+			sessions = append(sessions, s)
+		}
+	}
+
+	selected := make(map[string]struct{})
+	for _, s := range selections {
+		if s.Category == categorySession {
+			selected[s.ID] = struct{}{}
+		}
+	}
+
+	var targets []tmux.Session
+	var remaining []tmux.Session
+	for _, s := range sessions {
+		if _, ok := selected[s.ID]; ok {
+			targets = append(targets, s)
+		} else {
+			remaining = append(remaining, s)
+		}
+	}
+
+	if len(targets) == 0 {
+		log.Debug("No sessions selected")
+		return nil
+	}
+
+	names := make([]string, 0, len(targets))
+	for _, s := range targets {
+		names = append(names, s.Name)
+	}
+
+	ok, err := fzf.Confirm(ctx, fmt.Sprintf("Kill %d session(s): %s?", len(targets), strings.Join(names, ", ")))
+	if err != nil {
+		return errors.WrapIf(err, "fzf confirmation failed")
+	}
+
+	if !ok {
+		return nil
+	}
+
+	// kill the current session last, it may take this process with it
+	current, _ := tmux.GetCurrentSession(ctx)
+	if current != nil {
+		slices.SortStableFunc(targets, func(a, b tmux.Session) int {
+			return cmp.Compare(btoi(a.ID == current.ID), btoi(b.ID == current.ID))
+		})
+	}
+
+	for _, s := range targets {
+		if current != nil && s.ID == current.ID && len(remaining) > 0 {
+			log.Infof("Switching to session %s before killing the current one", remaining[0].Name)
+			if err := Open(ctx, categorySession, remaining[0].ID); err != nil {
+				return errors.WrapIf(err, "failed to switch away from the current session")
+			}
+		}
+
+		sctx, id, err := serverContext(ctx, categorySession, s.ID)
+		if err != nil {
+			return err
+		}
+
+		log.Infof("Killing tmux session: %s", s.Name)
+		if err := tmux.SessionKill(sctx, id); err != nil {
+			return errors.WrapIff(err, "failed to kill session: %s", s.Name)
+		}
+	}
+
+	return UpdateContentAndHeader(ctx, listener)
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
 }
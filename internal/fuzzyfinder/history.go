@@ -0,0 +1,31 @@
+package fuzzyfinder
+
+import (
+	"context"
+	"tmux-session-launcher/internal/history"
+	"tmux-session-launcher/internal/tmux"
+	"tmux-session-launcher/pkg/logger"
+)
+
+// TrackHistory records the attaches and renames done through the tmux package, for frecency
+// ranking and the recent session stack. Failing only costs ranking accuracy.
+func TrackHistory() {
+	tmux.OnAttach(func(_ context.Context, previous string, session *tmux.Session) {
+		if err := history.RecordSession(previous, session.Name); err != nil {
+			logger.Warnf("Failed to record session history: %v", err)
+		}
+	})
+
+	tmux.OnRename(func(_ context.Context, oldName, newName string) {
+		if err := history.RenameSession(oldName, newName); err != nil {
+			logger.Warnf("Failed to rename session history: %v", err)
+		}
+	})
+}
+
+// recordDirectory counts an open of the directory, whether its session exists or not
+func recordDirectory(path string) {
+	if err := history.Record(history.KindDirectory, path); err != nil {
+		logger.Warnf("Failed to record directory history: %v", err)
+	}
+}
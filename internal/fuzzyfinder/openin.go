@@ -0,0 +1,288 @@
+package fuzzyfinder
+
+import (
+	"context"
+	"os"
+	"strings"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/internal/fzf"
+	"tmux-session-launcher/internal/naming"
+	"tmux-session-launcher/internal/popup"
+	"tmux-session-launcher/internal/tmux"
+	"tmux-session-launcher/pkg/logger"
+	"tmux-session-launcher/pkg/util"
+
+	"emperror.dev/errors"
+)
+
+// openInTarget is a row of the open-in menu
+type openInTarget struct {
+	Name string
+	// Key is the jump label selecting the row
+	Key string
+	// Open receives the ID of the row, the path of directories
+	Open func(ctx context.Context, cfg *config.Config, id string) error
+}
+
+// directoryTargets are the places a directory can be opened in, in menu order
+var directoryTargets = []openInTarget{
+	{Name: "split-horizontal", Key: "h", Open: openInSplit(tmux.SplitHorizontal)},
+	{Name: "split-vertical", Key: "v", Open: openInSplit(tmux.SplitVertical)},
+	{Name: "window", Key: "w", Open: openInWindow},
+	{Name: "window-in-session", Key: "o", Open: openInOtherSession},
+	{Name: "popup", Key: "p", Open: openInPopup},
+	{Name: "session", Key: "s", Open: func(ctx context.Context, _ *config.Config, path string) error {
+		return openDirectory(ctx, path)
+	}},
+}
+
+var (
+	attachTarget = openInTarget{Name: "attach", Key: "a", Open: func(ctx context.Context, _ *config.Config, id string) error {
+		return Open(ctx, categorySession, id)
+	}}
+	linkWindowTarget   = openInTarget{Name: "link-window", Key: "l", Open: linkSessionWindow}
+	joinPaneTarget     = openInTarget{Name: "join-pane", Key: "j", Open: joinOriginPane}
+	windowInPathTarget = openInTarget{Name: "window-in-path", Key: "w", Open: openWindowInSession}
+)
+
+// sessionTargets bring a session, or part of it, to the origin one, in menu order. Windows and
+// panes only move between two different sessions, and the picker's own pane never moves.
+func sessionTargets(ctx context.Context, id string) []openInTarget {
+	log := logger.WithPrefix("fuzzyfinder.sessionTargets")
+
+	pane, session, err := origin(ctx)
+	if err != nil {
+		log.Debugf("No origin to move windows or panes to: %v", err)
+		return []openInTarget{attachTarget, windowInPathTarget}
+	}
+
+	if session.ID == id {
+		return []openInTarget{attachTarget, windowInPathTarget}
+	}
+
+	targets := []openInTarget{attachTarget, linkWindowTarget}
+	if pane != os.Getenv("TMUX_PANE") {
+		targets = append(targets, joinPaneTarget)
+	}
+
+	return append(targets, windowInPathTarget)
+}
+
+// recordOrigin remembers the pane the launcher was opened from before fzf takes over the client.
+// Inside a popup, the process that opened it already did.
+func recordOrigin(ctx context.Context) error {
+	if os.Getenv(popup.EnvOriginPane) != "" {
+		return nil
+	}
+
+	pane, err := tmux.GetCurrentPane(ctx)
+	if err != nil {
+		return err
+	}
+
+	return os.Setenv(popup.EnvOriginPane, pane)
+}
+
+// origin returns the pane the launcher was opened from and its session
+func origin(ctx context.Context) (string, *tmux.Session, error) {
+	pane := os.Getenv(popup.EnvOriginPane)
+	if pane == "" {
+		return "", nil, errors.New("launcher not opened from a tmux pane")
+	}
+
+	session, err := tmux.GetSession(ctx, pane)
+	if err != nil {
+		return "", nil, errors.WrapIff(err, "failed to get session of pane: %s", pane)
+	}
+
+	return pane, session, nil
+}
+
+// selectOpenInTarget asks for one of the targets through their jump labels, nil when cancelled
+func selectOpenInTarget(ctx context.Context, cfg *config.Config, targets []openInTarget) (*openInTarget, error) {
+	log := logger.WithPrefix("fuzzyfinder.selectOpenInTarget")
+
+	names := make([]string, 0, len(targets))
+	labels := make([]string, 0, len(targets))
+	for _, target := range targets {
+		names = append(names, target.Name)
+		labels = append(labels, target.Key)
+	}
+
+	args := []string{
+		"--bind", "result:jump,jump:accept",
+		"--jump-labels", strings.Join(labels, ""),
+	}
+	args = append(args, userOptions(cfg)...)
+
+	output, errOutput, err := fzf.SelectWithString(ctx, args, strings.Join(names, "\n"))
+	if err != nil {
+		if errors.Is(err, fzf.ErrUserCancelled) {
+			return nil, nil
+		}
+
+		log.Errorf("fzf selection failed: %v", errOutput)
+		return nil, errors.WrapIf(err, "fzf selection failed")
+	}
+
+	for i := range targets {
+		if targets[i].Name == output {
+			return &targets[i], nil
+		}
+	}
+
+	return nil, errors.Errorf("invalid input: %s", output)
+}
+
+func openInSplit(direction tmux.SplitDirection) func(context.Context, *config.Config, string) error {
+	return func(ctx context.Context, _ *config.Config, path string) error {
+		_, err := tmux.PaneCreateWithOptions(ctx, tmux.PaneOptions{Path: path, Direction: direction})
+		return err
+	}
+}
+
+func openInWindow(ctx context.Context, cfg *config.Config, path string) error {
+	name, err := naming.WindowName(cfg, path)
+	if err != nil {
+		return errors.WrapIff(err, "failed to name window for: %s", path)
+	}
+
+	_, _, err = tmux.WindowCreateWithOptions(ctx, tmux.WindowOptions{Name: name, Path: path})
+
+	return err
+}
+
+// openInOtherSession asks for another session, opens the window there and switches to it
+func openInOtherSession(ctx context.Context, cfg *config.Config, path string) error {
+	log := logger.WithPrefix("fuzzyfinder.openInOtherSession")
+
+	sessions, err := tmux.GetSessions(ctx)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get sessions")
+	}
+
+	byName := make(map[string]tmux.Session, len(sessions))
+	names := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		if session.Current {
+			continue
+		}
+
+		byName[session.Name] = session
+		names = append(names, session.Name)
+	}
+
+	if len(names) == 0 {
+		return errors.New("no other session to open the window in")
+	}
+
+	args := []string{"--prompt", "session> "}
+	args = append(args, userOptions(cfg)...)
+
+	output, errOutput, err := fzf.SelectWithString(ctx, args, strings.Join(names, "\n"))
+	if err != nil {
+		if errors.Is(err, fzf.ErrUserCancelled) {
+			return nil
+		}
+
+		log.Errorf("fzf selection failed: %v", errOutput)
+		return errors.WrapIf(err, "fzf selection failed")
+	}
+
+	session, ok := byName[output]
+	if !ok {
+		return errors.Errorf("invalid session: %s", output)
+	}
+
+	name, err := naming.WindowName(cfg, path)
+	if err != nil {
+		return errors.WrapIff(err, "failed to name window for: %s", path)
+	}
+
+	_, _, err = tmux.WindowCreateWithOptions(ctx, tmux.WindowOptions{
+		Target: session.ID + ":",
+		Name:   name,
+		Path:   path,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tmux.SessionAttach(ctx, session.ID)
+}
+
+// openInPopup runs the user's shell in the directory, in a popup closed when the shell exits
+func openInPopup(ctx context.Context, cfg *config.Config, path string) error {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "sh"
+	}
+
+	popup := cfg.PopupOptions()
+
+	title := popup.Title
+	if title == "" {
+		title = util.TruncateHomePath(path)
+	}
+
+	return tmux.DisplayPopup(ctx, util.ShellQuote(shell), tmux.PopupOptions{
+		Width:  popup.Width,
+		Height: popup.Height,
+		Border: popup.Border,
+		Title:  title,
+		Path:   path,
+	})
+}
+
+// linkSessionWindow links the active window of the session into the origin session
+func linkSessionWindow(ctx context.Context, _ *config.Config, id string) error {
+	_, session, err := origin(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tmux.WindowLink(ctx, id+":", session.ID)
+}
+
+// joinOriginPane moves the origin pane to the active window of the session and follows it
+func joinOriginPane(ctx context.Context, _ *config.Config, id string) error {
+	pane, _, err := origin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tmux.PaneJoin(ctx, pane, id+":"); err != nil {
+		return err
+	}
+
+	return tmux.SessionAttach(ctx, id)
+}
+
+// openWindowInSession opens a window in the root directory of the session and switches to it
+func openWindowInSession(ctx context.Context, cfg *config.Config, id string) error {
+	session, err := tmux.GetSession(ctx, id)
+	if err != nil {
+		return errors.WrapIff(err, "failed to get session: %s", id)
+	}
+
+	path, err := tmux.SessionRootPath(ctx, session.Name)
+	if err != nil {
+		return errors.WrapIff(err, "failed to get root path of session: %s", session.Name)
+	}
+
+	name, err := naming.WindowName(cfg, path)
+	if err != nil {
+		return errors.WrapIff(err, "failed to name window for: %s", path)
+	}
+
+	_, _, err = tmux.WindowCreateWithOptions(ctx, tmux.WindowOptions{
+		Target: id + ":",
+		Name:   name,
+		Path:   path,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tmux.SessionAttach(ctx, id)
+}
@@ -0,0 +1,148 @@
+package fuzzyfinder
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"tmux-session-launcher/internal/tmux"
+
+	"emperror.dev/errors"
+)
+
+const (
+	previewTreeDepth   = "2"
+	previewReadmeLines = 20
+)
+
+func GetPreview(ctx context.Context, category string, id string) (string, error) {
+	ctx, id, err := serverContext(ctx, category, id)
+	if err != nil {
+		return "", err
+	}
+
+	switch category {
+	case categorySession, categoryWindow, categoryPane:
+		return previewSession(ctx, id)
+	case categoryDirectory:
+		return previewDirectory(ctx, id)
+	default:
+		return previewProvider(ctx, category, id)
+	}
+}
+
+func previewSession(ctx context.Context, id string) (string, error) {
+	content, err := tmux.PaneCapture(ctx, id)
+	if err != nil {
+		return "", errors.WrapIff(err, "failed to capture: %s", id)
+	}
+
+	return content, nil
+}
+
+func previewDirectory(ctx context.Context, path string) (string, error) {
+	var output strings.Builder
+
+	listing, err := listDirectory(ctx, path)
+	if err != nil {
+		return "", errors.WrapIff(err, "failed to list directory: %s", path)
+	}
+	output.WriteString(listing)
+
+	readme := findReadme(path)
+	if readme == "" {
+		return output.String(), nil
+	}
+
+	head, err := readHead(readme, previewReadmeLines)
+	if err != nil {
+		// the listing is still useful on its own
+		return output.String(), nil
+	}
+
+	output.WriteString("\n")
+	output.WriteString(colorCategoryDir(filepath.Base(readme)))
+	output.WriteString("\n")
+	output.WriteString(head)
+
+	return output.String(), nil
+}
+
+// listDirectory prefers tree(1) and falls back to a flat listing when it is not installed.
+func listDirectory(ctx context.Context, path string) (string, error) {
+	if treePath, err := exec.LookPath("tree"); err == nil {
+		args := []string{"-L", previewTreeDepth, "--dirsfirst", "--noreport"}
+		if !noColor() {
+			args = append(args, "-C")
+		}
+
+		cmd := exec.CommandContext(ctx, treePath, append(args, path)...)
+
+		if output, err := cmd.Output(); err == nil {
+			return string(output), nil
+		}
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return "", err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].IsDir() && !entries[j].IsDir()
+	})
+
+	var output strings.Builder
+	output.WriteString(colorPath(path))
+	output.WriteString("\n")
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			output.WriteString(colorCategoryDir(entry.Name() + "/"))
+		} else {
+			output.WriteString(entry.Name())
+		}
+		output.WriteString("\n")
+	}
+
+	return output.String(), nil
+}
+
+func findReadme(path string) string {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return ""
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToLower(entry.Name()), "readme") {
+			return filepath.Join(path, entry.Name())
+		}
+	}
+
+	return ""
+}
+
+func readHead(path string, lines int) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var output strings.Builder
+	scanner := bufio.NewScanner(file)
+	for i := 0; i < lines && scanner.Scan(); i++ {
+		output.WriteString(scanner.Text())
+		output.WriteString("\n")
+	}
+
+	return output.String(), scanner.Err()
+}
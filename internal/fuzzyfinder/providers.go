@@ -0,0 +1,95 @@
+package fuzzyfinder
+
+import (
+	"context"
+	"fmt"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/internal/mode"
+	"tmux-session-launcher/internal/provider"
+	"tmux-session-launcher/internal/tmux"
+	"tmux-session-launcher/pkg/logger"
+
+	"emperror.dev/errors"
+)
+
+// findProvider returns the provider whose rows use the given category, or mode, or nil
+func findProvider(name string) *config.ProviderConfig {
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Warnf("Failed to load configuration: %v", err)
+		return nil
+	}
+
+	return cfg.FindProvider(name)
+}
+
+// providerEntries lists the rows of the provider owning the mode, if any
+func providerEntries(ctx context.Context, m mode.Mode) []Entry {
+	p := findProvider(m.String())
+	if p == nil {
+		return nil
+	}
+
+	rows, err := provider.List(ctx, *p)
+	if err != nil {
+		logger.Warnf("Failed to list provider rows: %v", err)
+		return nil
+	}
+
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, Entry{
+			Category: p.Name,
+			ID:       row.ID,
+			Name:     row.Name,
+			Path:     row.Path,
+		})
+	}
+
+	return entries
+}
+
+// openProvider runs the select action of the provider owning the category
+func openProvider(ctx context.Context, category string, id string) error {
+	p := findProvider(category)
+	if p == nil {
+		return fmt.Errorf("invalid category: %s", category)
+	}
+
+	row, err := provider.Find(ctx, *p, id)
+	if err != nil {
+		return err
+	}
+
+	switch p.Action {
+	case config.ProviderActionAttach:
+		return tmux.SessionAttach(ctx, "="+row.ID)
+	case config.ProviderActionCommand:
+		return provider.RunCommand(ctx, *p, *row)
+	default:
+		if row.Path == "" {
+			return errors.Errorf("%s row has no path: %s", p.Name, row.ID)
+		}
+
+		return openDirectory(ctx, row.Path)
+	}
+}
+
+// previewProvider previews the path of a provider row, or nothing when it has none
+func previewProvider(ctx context.Context, category string, id string) (string, error) {
+	p := findProvider(category)
+	if p == nil {
+		return "", fmt.Errorf("invalid category: %s", category)
+	}
+
+	row, err := provider.Find(ctx, *p, id)
+	if err != nil {
+		return "", err
+	}
+
+	if row.Path == "" {
+		return row.Name, nil
+	}
+
+	return previewDirectory(ctx, row.Path)
+}
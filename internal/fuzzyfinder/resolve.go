@@ -0,0 +1,74 @@
+package fuzzyfinder
+
+import (
+	"strings"
+)
+
+// Resolve narrows entries down to those matching query, trying exact names first, then
+// prefixes, then fuzzy subsequences. The first stage with any match wins. An exact session
+// match beats an exact directory match, as the session usually belongs to that directory.
+func Resolve(entries []Entry, query string) []Entry {
+	exact := filterEntries(entries, func(name string) bool {
+		return name == query
+	})
+
+	if sessions := filterCategory(exact, categorySession); len(sessions) == 1 {
+		return sessions
+	}
+
+	if len(exact) > 0 {
+		return exact
+	}
+
+	q := strings.ToLower(query)
+
+	prefix := filterEntries(entries, func(name string) bool {
+		return strings.HasPrefix(strings.ToLower(name), q)
+	})
+
+	if len(prefix) > 0 {
+		return prefix
+	}
+
+	return filterEntries(entries, func(name string) bool {
+		return isSubsequence(q, strings.ToLower(name))
+	})
+}
+
+func filterEntries(entries []Entry, match func(name string) bool) []Entry {
+	var result []Entry
+	for _, e := range entries {
+		if match(e.Name) {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+func filterCategory(entries []Entry, category string) []Entry {
+	var result []Entry
+	for _, e := range entries {
+		if e.Category == category {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+// isSubsequence reports whether every rune of query appears in s in order, like fzf's fuzzy match.
+func isSubsequence(query, s string) bool {
+	rest := []rune(query)
+	for _, r := range s {
+		if len(rest) == 0 {
+			break
+		}
+
+		if r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+
+	return len(rest) == 0
+}
@@ -0,0 +1,90 @@
+package fuzzyfinder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/internal/tmux"
+	"tmux-session-launcher/pkg/logger"
+)
+
+// serverSeparator joins the server label and the tmux id of entries from the extra servers
+const serverSeparator = ":"
+
+// extraServers returns the configured servers listed next to the main one
+func extraServers() []tmux.Server {
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Warnf("Failed to load configuration: %v", err)
+		return nil
+	}
+
+	servers := make([]tmux.Server, 0, len(cfg.Tmux.Servers))
+	for _, spec := range cfg.Tmux.Servers {
+		server := tmux.ParseServer(config.ExpandPath(spec))
+		if server == tmux.DefaultServer() {
+			continue
+		}
+
+		servers = append(servers, server)
+	}
+
+	return servers
+}
+
+// fromServers lists entries of the main server and of every extra one. With extra servers every
+// entry is labeled, and the ids of the extra ones carry their label so they can be resolved back.
+func fromServers(ctx context.Context, list func(ctx context.Context) []Entry) []Entry {
+	servers := extraServers()
+
+	entries := list(ctx)
+	if len(servers) == 0 {
+		return entries
+	}
+
+	for i := range entries {
+		setServer(&entries[i], tmux.DefaultServer().Label())
+	}
+
+	for _, server := range servers {
+		for _, e := range list(tmux.WithServer(ctx, server)) {
+			e.ID = server.Label() + serverSeparator + e.ID
+			setServer(&e, server.Label())
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+func setServer(e *Entry, label string) {
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]string)
+	}
+
+	e.Metadata["server"] = label
+}
+
+// serverContext resolves the id of a tmux entry to the context of its server and the bare tmux id.
+// Other categories and entries of the main server are returned unchanged.
+func serverContext(ctx context.Context, category string, id string) (context.Context, string, error) {
+	switch category {
+	case categorySession, categoryWindow, categoryPane:
+	default:
+		return ctx, id, nil
+	}
+
+	label, tmuxID, ok := strings.Cut(id, serverSeparator)
+	if !ok {
+		return ctx, id, nil
+	}
+
+	for _, server := range extraServers() {
+		if server.Label() == label {
+			return tmux.WithServer(ctx, server), tmuxID, nil
+		}
+	}
+
+	return ctx, id, fmt.Errorf("unknown tmux server: %s", label)
+}
@@ -0,0 +1,55 @@
+package fuzzyfinder
+
+import (
+	"fmt"
+	"os"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/pkg/logger"
+	"tmux-session-launcher/pkg/style"
+
+	"github.com/fatih/color"
+)
+
+var (
+	colorDefault         = fmt.Sprint
+	colorCategorySession = color.New(color.FgHiCyan, color.Italic).Sprint
+	colorCategoryDir     = color.New(color.FgHiBlue, color.Italic).Sprint
+	colorCategoryWindow  = color.New(color.FgHiMagenta, color.Italic).Sprint
+	colorCategoryPane    = color.New(color.FgHiYellow, color.Italic).Sprint
+	colorCurrentSession  = color.New(color.FgHiGreen, color.Bold).Sprint
+	colorPath            = color.New(color.FgHiBlack, color.Italic).Sprint
+	colorHeader          = color.New(color.Faint).Sprint
+	colorMute            = color.RGB(0, 0, 0).Sprint
+)
+
+// setTheme replaces the colors above, keeping the current one for any style that does not parse.
+func setTheme(theme config.ThemeConfig) {
+	log := logger.WithPrefix("fuzzyfinder.setTheme")
+
+	for _, c := range []struct {
+		spec string
+		fn   *func(a ...any) string
+	}{
+		{theme.Session, &colorCategorySession},
+		{theme.Directory, &colorCategoryDir},
+		{theme.Window, &colorCategoryWindow},
+		{theme.Pane, &colorCategoryPane},
+		{theme.Current, &colorCurrentSession},
+		{theme.Path, &colorPath},
+		{theme.Header, &colorHeader},
+		{theme.Mute, &colorMute},
+	} {
+		parsed, err := style.Parse(c.spec)
+		if err != nil {
+			log.Warnf("Ignoring theme color %q: %v", c.spec, err)
+			continue
+		}
+
+		*c.fn = parsed.Sprint
+	}
+}
+
+// noColor reports whether the user asked for plain output, see https://no-color.org
+func noColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
@@ -8,6 +8,7 @@ import (
 
 var (
 	ErrUserCancelled = errors.New("user cancelled the operation")
+	ErrNoMatch       = errors.New("no match for the query")
 )
 
 func handleExitCodeErr(err error) error {
@@ -18,6 +19,10 @@ func handleExitCodeErr(err error) error {
 			return ErrUserCancelled
 		}
 
+		if exitCode == 1 {
+			return ErrNoMatch
+		}
+
 		return errors.Wrapf(err, "fzf failed with exit code: %d", exitCode)
 	}
 
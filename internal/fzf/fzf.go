@@ -20,6 +20,29 @@ func Select(ctx context.Context, args []string, stdin io.Reader, stdout io.Write
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
 
+	return run(cmd)
+}
+
+// SelectListening is Select with fzf's HTTP server enabled on the listener's socket.
+func SelectListening(ctx context.Context, listener *Listener, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	// left over by an fzf that was killed
+	if err := os.Remove(listener.Socket); err != nil && !os.IsNotExist(err) {
+		return errors.WrapIf(err, "failed to remove stale fzf socket")
+	}
+	defer os.Remove(listener.Socket)
+
+	args = append([]string{"--listen=" + listener.Socket}, args...)
+
+	cmd := exec.CommandContext(ctx, "fzf", args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(), "FZF_API_KEY="+listener.APIKey)
+
+	return run(cmd)
+}
+
+func run(cmd *exec.Cmd) error {
 	if err := cmd.Start(); err != nil {
 		return errors.WrapIf(err, "failed to start fzf command")
 	}
@@ -48,7 +71,53 @@ func SelectWithString(ctx context.Context, args []string, input string) (string,
 	return strings.TrimSpace(stdout.String()), strings.TrimSpace(stderr.String()), err
 }
 
-func UpdateContentAndHeader(ctx context.Context, port int, header string) error {
+// Prompt uses fzf as a single line input, returning whatever the user typed.
+func Prompt(ctx context.Context, prompt, query, header string) (string, error) {
+	args := []string{
+		"--print-query",
+		"--disabled",
+		"--no-info",
+		"--prompt", prompt,
+		"--query", query,
+	}
+
+	if header != "" {
+		args = append(args, "--header", header)
+	}
+
+	output, _, err := SelectWithString(ctx, args, "")
+	if err != nil && !errors.Is(err, ErrNoMatch) {
+		return "", err
+	}
+
+	// --print-query puts the query on the first line
+	query, _, _ = strings.Cut(output, "\n")
+
+	return strings.TrimSpace(query), nil
+}
+
+// Confirm asks a yes/no question, defaulting to no.
+func Confirm(ctx context.Context, question string) (bool, error) {
+	args := []string{
+		"--no-info",
+		"--no-sort",
+		"--header", question,
+		"--prompt", "Confirm> ",
+	}
+
+	output, _, err := SelectWithString(ctx, args, "no\nyes")
+	if err != nil {
+		if errors.Is(err, ErrUserCancelled) || errors.Is(err, ErrNoMatch) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return output == "yes", nil
+}
+
+func UpdateContentAndHeader(ctx context.Context, listener *Listener, header string) error {
 	executable, err := os.Executable()
 	if err != nil {
 		return errors.WrapIf(err, "failed to get executable path")
@@ -59,21 +128,21 @@ func UpdateContentAndHeader(ctx context.Context, port int, header string) error
 	bodyMove := "first"
 
 	g, gCtx := errgroup.WithContext(ctx)
-	g.Go(func() error { return sendRequest(gCtx, port, bodyHeader) })
-	g.Go(func() error { return sendRequest(gCtx, port, bodyContent) })
-	g.Go(func() error { return sendRequest(gCtx, port, bodyMove) })
+	g.Go(func() error { return sendRequest(gCtx, listener, bodyHeader) })
+	g.Go(func() error { return sendRequest(gCtx, listener, bodyContent) })
+	g.Go(func() error { return sendRequest(gCtx, listener, bodyMove) })
 
 	return g.Wait()
 }
 
-func sendRequest(ctx context.Context, port int, body string) error {
+func sendRequest(ctx context.Context, listener *Listener, body string) error {
 	log := logger.WithPrefix("fzf.sendRequest")
 	log.Debugf("Sending request with body: %.200s", body)
 
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
-		fmt.Sprintf("http://localhost:%d", port),
+		listener.url(),
 		strings.NewReader(body),
 	)
 
@@ -81,8 +150,9 @@ func sendRequest(ctx context.Context, port int, body string) error {
 		log.Errorf("Failed to create request: %v", err)
 		return errors.WrapIf(err, "failed to create HTTP request")
 	}
+	req.Header.Set("x-api-key", listener.APIKey)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := listener.client.Do(req)
 	if err != nil {
 		log.Errorf("Failed to send request: %v", err)
 		return errors.WrapIf(err, "failed to send HTTP request")
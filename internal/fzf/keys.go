@@ -0,0 +1,54 @@
+package fzf
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// namedKeys are the fzf key names that are not a modifier followed by a letter
+var namedKeys = map[string]struct{}{
+	"tab": {}, "shift-tab": {}, "btab": {}, "esc": {}, "enter": {}, "return": {}, "space": {},
+	"bspace": {}, "bs": {}, "del": {}, "delete": {}, "insert": {}, "home": {}, "end": {},
+	"up": {}, "down": {}, "left": {}, "right": {},
+	"page-up": {}, "pgup": {}, "page-down": {}, "pgdn": {},
+	"shift-up": {}, "shift-down": {}, "shift-left": {}, "shift-right": {}, "shift-delete": {},
+	"alt-up": {}, "alt-down": {}, "alt-left": {}, "alt-right": {},
+	"alt-shift-up": {}, "alt-shift-down": {}, "alt-shift-left": {}, "alt-shift-right": {},
+	"alt-enter": {}, "alt-space": {}, "alt-bspace": {}, "alt-bs": {},
+	"ctrl-space": {}, "ctrl-delete": {}, `ctrl-\`: {}, "ctrl-]": {}, "ctrl-^": {}, "ctrl-/": {},
+	"left-click": {}, "right-click": {}, "double-click": {},
+	"scroll-up": {}, "scroll-down": {}, "shift-scroll-up": {}, "shift-scroll-down": {},
+	"f1": {}, "f2": {}, "f3": {}, "f4": {}, "f5": {}, "f6": {},
+	"f7": {}, "f8": {}, "f9": {}, "f10": {}, "f11": {}, "f12": {},
+}
+
+// IsValidKey reports whether fzf accepts key in a --bind expression.
+func IsValidKey(key string) bool {
+	if _, ok := namedKeys[key]; ok {
+		return true
+	}
+
+	if rest, ok := strings.CutPrefix(key, "ctrl-alt-"); ok {
+		return isLetter(rest)
+	}
+
+	if rest, ok := strings.CutPrefix(key, "ctrl-"); ok {
+		return isLetter(rest)
+	}
+
+	if rest, ok := strings.CutPrefix(key, "alt-"); ok {
+		return isSingleChar(rest)
+	}
+
+	return isSingleChar(key)
+}
+
+func isLetter(s string) bool {
+	return len(s) == 1 && s[0] >= 'a' && s[0] <= 'z'
+}
+
+// isSingleChar accepts one printable character, except the separators of the bind syntax
+func isSingleChar(s string) bool {
+	r, size := utf8.DecodeRuneInString(s)
+	return size == len(s) && r != utf8.RuneError && r > ' ' && !strings.ContainsRune(",:+", r)
+}
@@ -0,0 +1,47 @@
+package fzf
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+
+	"emperror.dev/errors"
+)
+
+// Listener is the endpoint of fzf's --listen server along with the key guarding it.
+type Listener struct {
+	// Socket is the unix socket fzf listens on (fzf 0.54 or later), its path ends in .sock as
+	// fzf requires
+	Socket string
+	APIKey string
+	client *http.Client
+}
+
+// NewListener prepares the socket path and a random API key for a new fzf instance. fzf creates
+// the socket itself, so there is no port to pick that another process could take meanwhile.
+func NewListener(socket string) (*Listener, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.WrapIf(err, "failed to generate api key")
+	}
+
+	return &Listener{
+		Socket: socket,
+		APIKey: hex.EncodeToString(key),
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}, nil
+}
+
+// url is what requests are sent to, the host is ignored as the transport dials the socket
+func (l *Listener) url() string {
+	return "http://fzf/"
+}
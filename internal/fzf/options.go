@@ -0,0 +1,79 @@
+package fzf
+
+import "strings"
+
+// EnvLauncherOptions holds extra fzf options for the launcher, in the same format as FZF_DEFAULT_OPTS
+const EnvLauncherOptions = "FZF_LAUNCHER_OPTS"
+
+// optionValue tells whether an option takes an argument of its own
+type optionValue int
+
+const (
+	noValue optionValue = iota
+	requiredValue
+	// optionalValue is only taken from the next argument when that is not an option itself
+	optionalValue
+)
+
+// reservedOptions are set by the launcher itself, overriding them breaks the RPC or the parsing of
+// the selection.
+var reservedOptions = map[string]optionValue{
+	"--listen":        optionalValue,
+	"--listen-unsafe": optionalValue,
+	"--delimiter":     requiredValue,
+	"-d":              requiredValue,
+	"--with-nth":      requiredValue,
+	"--accept-nth":    requiredValue,
+	"--expect":        requiredValue,
+	"--print-query":   noValue,
+}
+
+// IsReservedOption reports whether arg sets an option the launcher depends on.
+func IsReservedOption(arg string) bool {
+	_, _, ok := reservedOption(arg)
+	return ok
+}
+
+// FilterOptions drops the reserved options, and their values, from args and returns them separately.
+func FilterOptions(args []string) (kept []string, dropped []string) {
+	for i := 0; i < len(args); i++ {
+		name, hasValue, ok := reservedOption(args[i])
+		if !ok {
+			kept = append(kept, args[i])
+			continue
+		}
+
+		dropped = append(dropped, name)
+		if hasValue || i+1 == len(args) {
+			continue
+		}
+
+		switch reservedOptions[name] {
+		case requiredValue:
+			i++
+		case optionalValue:
+			if !strings.HasPrefix(args[i+1], "-") {
+				i++
+			}
+		}
+	}
+
+	return kept, dropped
+}
+
+// reservedOption returns the reserved option set by arg and whether arg carries its value
+func reservedOption(arg string) (string, bool, bool) {
+	name, _, hasValue := strings.Cut(arg, "=")
+	if _, ok := reservedOptions[name]; ok {
+		return name, hasValue, true
+	}
+
+	// short options take their value attached, e.g. -d:
+	if len(arg) > 2 && !strings.HasPrefix(arg, "--") {
+		if _, ok := reservedOptions[arg[:2]]; ok {
+			return arg[:2], true, true
+		}
+	}
+
+	return "", false, false
+}
@@ -0,0 +1,81 @@
+package fzf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		kept    []string
+		dropped []string
+	}{
+		{
+			name: "none reserved",
+			args: []string{"--height", "40%", "--reverse"},
+			kept: []string{"--height", "40%", "--reverse"},
+		},
+		{
+			name:    "inline value",
+			args:    []string{"--delimiter=:", "--reverse", "--with-nth=2"},
+			kept:    []string{"--reverse"},
+			dropped: []string{"--delimiter", "--with-nth"},
+		},
+		{
+			name:    "separate value",
+			args:    []string{"--delimiter", ":", "--reverse", "--accept-nth", "1"},
+			kept:    []string{"--reverse"},
+			dropped: []string{"--delimiter", "--accept-nth"},
+		},
+		{
+			name:    "attached short value",
+			args:    []string{"-d:", "-m"},
+			kept:    []string{"-m"},
+			dropped: []string{"-d"},
+		},
+		{
+			name:    "listen without address",
+			args:    []string{"--listen", "--reverse"},
+			kept:    []string{"--reverse"},
+			dropped: []string{"--listen"},
+		},
+		{
+			name:    "listen with address",
+			args:    []string{"--listen", "localhost:6266", "--reverse"},
+			kept:    []string{"--reverse"},
+			dropped: []string{"--listen"},
+		},
+		{
+			name:    "listen-unsafe with inline address",
+			args:    []string{"--listen-unsafe=6266", "--reverse"},
+			kept:    []string{"--reverse"},
+			dropped: []string{"--listen-unsafe"},
+		},
+		{
+			name:    "listen last",
+			args:    []string{"--reverse", "--listen"},
+			kept:    []string{"--reverse"},
+			dropped: []string{"--listen"},
+		},
+		{
+			name:    "flag without value",
+			args:    []string{"--print-query", "--reverse"},
+			kept:    []string{"--reverse"},
+			dropped: []string{"--print-query"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, dropped := FilterOptions(tt.args)
+			if !reflect.DeepEqual(kept, tt.kept) {
+				t.Errorf("kept = %q, want %q", kept, tt.kept)
+			}
+			if !reflect.DeepEqual(dropped, tt.dropped) {
+				t.Errorf("dropped = %q, want %q", dropped, tt.dropped)
+			}
+		})
+	}
+}
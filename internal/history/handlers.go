@@ -0,0 +1,67 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rodaine/table"
+	"github.com/urfave/cli/v3"
+)
+
+// HandlerShowHistory lists the recorded entries ranked by frecency
+func HandlerShowHistory(ctx context.Context, cmd *cli.Command) error {
+	history, err := Load()
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+
+	if len(history.Entries) == 0 {
+		fmt.Println("No history recorded")
+		return nil
+	}
+
+	now := time.Now()
+	tbl := table.New("kind", "key", "count", "last used", "score").WithWriter(os.Stdout)
+	for _, e := range history.Sorted(now) {
+		tbl.AddRow(e.Kind, e.Key, e.Count, e.LastUsed.Format(time.DateTime), e.Score(now))
+	}
+	tbl.Print()
+
+	return nil
+}
+
+// HandlerPruneHistory removes entries that were not used for the given number of days
+func HandlerPruneHistory(ctx context.Context, cmd *cli.Command) error {
+	days := cmd.Int("days")
+	if days < 0 {
+		return fmt.Errorf("days must not be negative")
+	}
+
+	var removed int
+	err := Update(func(history *History) error {
+		removed = history.Prune(time.Now().AddDate(0, 0, -days))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune history: %w", err)
+	}
+
+	fmt.Printf("Pruned %d entries not used in %d days\n", removed, days)
+	return nil
+}
+
+// HandlerClearHistory removes every recorded entry
+func HandlerClearHistory(ctx context.Context, cmd *cli.Command) error {
+	err := Update(func(history *History) error {
+		*history = History{Entries: []Entry{}}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear history: %w", err)
+	}
+
+	fmt.Println("History cleared")
+	return nil
+}
@@ -0,0 +1,288 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+)
+
+const (
+	KindSession   = "session"
+	KindDirectory = "directory"
+
+	// maxRecent bounds the most recently used session stack
+	maxRecent = 50
+)
+
+// History is the persisted usage log used to rank entries by frecency
+type History struct {
+	Entries []Entry `json:"entries"`
+	// Recent holds session names, most recently attached first
+	Recent []string `json:"recent,omitempty"`
+}
+
+// Entry counts how often and how recently a session or directory was opened
+type Entry struct {
+	Kind     string    `json:"kind"`
+	Key      string    `json:"key"`
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// getStatePath returns the path to the history file
+func getStatePath() string {
+	// Use XDG_STATE_HOME if set, otherwise fall back to ~/.local/state
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = os.ExpandEnv("$HOME")
+		}
+		stateDir = filepath.Join(homeDir, ".local", "state")
+	}
+	return filepath.Join(stateDir, "tmux-session-launcher", "history.json")
+}
+
+// GetStatePath returns the path to the history file (for external use)
+func GetStatePath() string {
+	return getStatePath()
+}
+
+// Load reads the history file, returning an empty history if it does not exist yet
+func Load() (*History, error) {
+	data, err := os.ReadFile(getStatePath())
+	if os.IsNotExist(err) {
+		return &History{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var history History
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+// Save writes the history file atomically so readers never see it half written. Changes go
+// through Update, which keeps concurrent launchers from overwriting each other.
+func Save(history *History) error {
+	statePath := getStatePath()
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(statePath), ".history-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), statePath)
+}
+
+// Update loads the history, applies fn and saves it, all under an exclusive lock shared with
+// every other process of the launcher
+func Update(fn func(history *History) error) error {
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	history, err := Load()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(history); err != nil {
+		return err
+	}
+
+	return Save(history)
+}
+
+// lock takes an flock on a file next to the history. The history file itself is replaced on
+// every save, so a lock on it would not outlive the first writer.
+func lock() (func(), error) {
+	lockPath := getStatePath() + ".lock"
+
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return func() {
+		_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
+
+// Record bumps the usage of the given entry and persists it
+func Record(kind, key string) error {
+	return Update(func(history *History) error {
+		history.Add(kind, key, time.Now())
+		return nil
+	})
+}
+
+// RecordSession bumps the usage of the attached session and pushes it on the recent stack,
+// right above previous (the session it was switched from) when that is known
+func RecordSession(previous, name string) error {
+	return Update(func(history *History) error {
+		history.Add(KindSession, name, time.Now())
+		if previous != "" {
+			history.Push(previous)
+		}
+		history.Push(name)
+		return nil
+	})
+}
+
+// RenameSession moves the recorded usage of a session to its new name
+func RenameSession(oldName, newName string) error {
+	return Update(func(history *History) error {
+		history.Rename(KindSession, oldName, newName)
+		return nil
+	})
+}
+
+// Add bumps the usage of the given entry in memory
+func (h *History) Add(kind, key string, now time.Time) {
+	for i := range h.Entries {
+		if h.Entries[i].Kind == kind && h.Entries[i].Key == key {
+			h.Entries[i].Count++
+			h.Entries[i].LastUsed = now
+			return
+		}
+	}
+
+	h.Entries = append(h.Entries, Entry{
+		Kind:     kind,
+		Key:      key,
+		Count:    1,
+		LastUsed: now,
+	})
+}
+
+// Push moves the session to the top of the recent stack
+func (h *History) Push(name string) {
+	recent := []string{name}
+	for _, n := range h.Recent {
+		if n != name && len(recent) < maxRecent {
+			recent = append(recent, n)
+		}
+	}
+
+	h.Recent = recent
+}
+
+// Rename rekeys the given entry, and the recent stack for sessions
+func (h *History) Rename(kind, oldKey, newKey string) {
+	for i := range h.Entries {
+		if h.Entries[i].Kind == kind && h.Entries[i].Key == oldKey {
+			h.Entries[i].Key = newKey
+		}
+	}
+
+	if kind != KindSession {
+		return
+	}
+
+	recent := h.Recent[:0]
+	for _, n := range h.Recent {
+		switch n {
+		case newKey:
+			// a dead session that used to have the new name
+		case oldKey:
+			recent = append(recent, newKey)
+		default:
+			recent = append(recent, n)
+		}
+	}
+
+	h.Recent = recent
+}
+
+// Score returns the frecency of the given entry, 0 if it was never used
+func (h *History) Score(kind, key string, now time.Time) float64 {
+	for _, e := range h.Entries {
+		if e.Kind == kind && e.Key == key {
+			return e.Score(now)
+		}
+	}
+
+	return 0
+}
+
+// Prune removes entries last used before the cutoff and returns how many were removed
+func (h *History) Prune(cutoff time.Time) int {
+	kept := h.Entries[:0]
+	for _, e := range h.Entries {
+		if e.LastUsed.After(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+
+	removed := len(h.Entries) - len(kept)
+	h.Entries = kept
+
+	return removed
+}
+
+// Sorted returns the entries ordered by descending frecency
+func (h *History) Sorted(now time.Time) []Entry {
+	entries := make([]Entry, len(h.Entries))
+	copy(entries, h.Entries)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Score(now) > entries[j].Score(now)
+	})
+
+	return entries
+}
+
+// Score weighs the use count by how recently the entry was used
+func (e Entry) Score(now time.Time) float64 {
+	age := now.Sub(e.LastUsed)
+
+	switch {
+	case age < time.Hour:
+		return float64(e.Count) * 4
+	case age < 24*time.Hour:
+		return float64(e.Count) * 2
+	case age < 7*24*time.Hour:
+		return float64(e.Count) * 0.5
+	default:
+		return float64(e.Count) * 0.25
+	}
+}
@@ -15,7 +15,7 @@ func (l *Launcher) setupHandlers() {
 	l.Server.RegisterHandler(rpc.MethodModeNext, handler.New(func(ctx context.Context, req *jrpc2.Request) (any, error) {
 		m := mode.Next()
 
-		err := fuzzyfinder.UpdateContentAndHeader(ctx)
+		err := fuzzyfinder.UpdateContentAndHeader(ctx, l.Fzf)
 		if err != nil {
 			return nil, errors.WrapIf(err, "failed to update fzf content and header")
 		}
@@ -26,7 +26,7 @@ func (l *Launcher) setupHandlers() {
 	l.Server.RegisterHandler(rpc.MethodModePrev, handler.New(func(ctx context.Context, req *jrpc2.Request) (any, error) {
 		m := mode.Prev()
 
-		err := fuzzyfinder.UpdateContentAndHeader(ctx)
+		err := fuzzyfinder.UpdateContentAndHeader(ctx, l.Fzf)
 		if err != nil {
 			return nil, errors.WrapIf(err, "failed to update fzf content and header")
 		}
@@ -47,6 +47,19 @@ func (l *Launcher) setupHandlers() {
 		return rpc.ContentResponse{Content: content}, nil
 	}))
 
+	l.Server.RegisterHandler(rpc.MethodContentPreview, handler.New(func(ctx context.Context, req *jrpc2.Request) (any, error) {
+		var params rpc.PreviewParams
+		if err := req.UnmarshalParams(&params); err != nil {
+			return nil, errors.WrapIf(err, "failed to unmarshal parameters")
+		}
+
+		preview, err := fuzzyfinder.GetPreview(ctx, params.Category, params.ID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to get preview")
+		}
+		return rpc.ContentResponse{Content: preview}, nil
+	}))
+
 	l.Server.RegisterHandler(rpc.MethodLauncherOpenIn, handler.New(func(ctx context.Context, req *jrpc2.Request) (any, error) {
 		var params rpc.OpenInParams
 		if err := req.UnmarshalParams(&params); err != nil {
@@ -60,4 +73,51 @@ func (l *Launcher) setupHandlers() {
 
 		return rpc.EmptyResponse{}, nil
 	}))
+
+	l.Server.RegisterHandler(rpc.MethodLauncherRename, handler.New(func(ctx context.Context, req *jrpc2.Request) (any, error) {
+		var params rpc.RenameParams
+		if err := req.UnmarshalParams(&params); err != nil {
+			return nil, errors.WrapIf(err, "failed to unmarshal parameters")
+		}
+
+		err := fuzzyfinder.Rename(ctx, l.Fzf, params.Category, params.ID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to rename")
+		}
+
+		return rpc.EmptyResponse{}, nil
+	}))
+
+	l.Server.RegisterHandler(rpc.MethodLauncherAction, handler.New(func(ctx context.Context, req *jrpc2.Request) (any, error) {
+		var params rpc.RunActionParams
+		if err := req.UnmarshalParams(&params); err != nil {
+			return nil, errors.WrapIf(err, "failed to unmarshal parameters")
+		}
+
+		err := fuzzyfinder.RunAction(ctx, params.Name, params.Category, params.ID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to run action")
+		}
+
+		return rpc.EmptyResponse{}, nil
+	}))
+
+	l.Server.RegisterHandler(rpc.MethodLauncherKill, handler.New(func(ctx context.Context, req *jrpc2.Request) (any, error) {
+		var params rpc.KillParams
+		if err := req.UnmarshalParams(&params); err != nil {
+			return nil, errors.WrapIf(err, "failed to unmarshal parameters")
+		}
+
+		selections := make([]fuzzyfinder.Selection, 0, len(params.Selections))
+		for _, s := range params.Selections {
+			selections = append(selections, fuzzyfinder.Selection{Category: s.Category, ID: s.ID})
+		}
+
+		err := fuzzyfinder.Kill(ctx, l.Fzf, selections)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to kill")
+		}
+
+		return rpc.EmptyResponse{}, nil
+	}))
 }
@@ -2,24 +2,38 @@ package launcher
 
 import (
 	"context"
-	"tmux-session-launcher/internal/rpc"
+	"os"
+	"strings"
 	"tmux-session-launcher/internal/fuzzyfinder"
+	"tmux-session-launcher/internal/fzf"
+	"tmux-session-launcher/internal/history"
+	"tmux-session-launcher/internal/mode"
+	"tmux-session-launcher/internal/rpc"
 	"tmux-session-launcher/internal/server"
+	"tmux-session-launcher/internal/tmux"
+	"tmux-session-launcher/pkg/logger"
 
+	"emperror.dev/errors"
 	"github.com/urfave/cli/v3"
 )
 
 type Launcher struct {
 	Server *server.Server
+	Fzf    *fzf.Listener
 }
 
-func NewLauncher(server *server.Server) *Launcher {
+func NewLauncher(server *server.Server, listener *fzf.Listener) *Launcher {
 	return &Launcher{
 		Server: server,
+		Fzf:    listener,
 	}
 }
 
 func (l *Launcher) Handler(ctx context.Context, cmd *cli.Command) error {
+	return l.Run(ctx, fuzzyfinder.LauncherOptions{})
+}
+
+func (l *Launcher) Run(ctx context.Context, opts fuzzyfinder.LauncherOptions) error {
 	err := l.Server.Start(ctx)
 	if err != nil {
 		return err
@@ -29,12 +43,92 @@ func (l *Launcher) Handler(ctx context.Context, cmd *cli.Command) error {
 
 	defer l.Server.Stop()
 
-	return fuzzyfinder.Launcher(ctx)
+	return fuzzyfinder.Launcher(ctx, l.Fzf, opts)
 }
 
 func HandlerLauncer(ctx context.Context, cmd *cli.Command) error {
-	srv := server.NewServer(rpc.SockAddress)
-	lcr := NewLauncher(srv)
+	lcr, err := newDefaultLauncher()
+	if err != nil {
+		return err
+	}
 
 	return lcr.Handler(ctx, cmd)
 }
+
+// HandlerSwitch opens the single entry matching the query, or the picker prefilled with it.
+func HandlerSwitch(ctx context.Context, cmd *cli.Command) error {
+	log := logger.WithPrefix("launcher.HandlerSwitch")
+
+	query := strings.Join(cmd.Args().Slice(), " ")
+	if query == "" {
+		return cli.Exit("a query is required", 1)
+	}
+
+	matches := fuzzyfinder.Resolve(fuzzyfinder.GetEntries(ctx, mode.ModeAll), query)
+
+	switch len(matches) {
+	case 0:
+		return errors.Errorf("nothing matches: %s", query)
+	case 1:
+		return fuzzyfinder.Open(ctx, matches[0].Category, matches[0].ID)
+	}
+
+	log.Debugf("%d entries match %q, falling back to the picker", len(matches), query)
+
+	lcr, err := newDefaultLauncher()
+	if err != nil {
+		return err
+	}
+
+	return lcr.Run(ctx, fuzzyfinder.LauncherOptions{Query: query})
+}
+
+// HandlerLast switches to the most recently attached session that still exists, other than the
+// current one.
+func HandlerLast(ctx context.Context, cmd *cli.Command) error {
+	hist, err := history.Load()
+	if err != nil {
+		return errors.WrapIf(err, "failed to load history")
+	}
+
+	sessions, err := tmux.GetSessions(ctx)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get sessions")
+	}
+
+	alive := make(map[string]tmux.Session, len(sessions))
+	for _, session := range sessions {
+		alive[session.Name] = session
+	}
+
+	for _, name := range hist.Recent {
+		session, ok := alive[name]
+		if !ok || session.Current {
+			// killed since, or the one we are in
+			continue
+		}
+
+		return tmux.SessionAttach(ctx, session.ID)
+	}
+
+	return cli.Exit("no previous session to switch to", 1)
+}
+
+func newDefaultLauncher() (*Launcher, error) {
+	address := rpc.NewSockAddress()
+
+	// fzf and the action subcommands it spawns inherit the environment
+	if err := os.Setenv(rpc.EnvSockAddress, address); err != nil {
+		return nil, err
+	}
+
+	// next to the RPC socket, fzf wants the .sock suffix
+	listener, err := fzf.NewListener(strings.TrimSuffix(address, ".sock") + "-fzf.sock")
+	if err != nil {
+		return nil, err
+	}
+
+	srv := server.NewServer(address)
+
+	return NewLauncher(srv, listener), nil
+}
@@ -0,0 +1,82 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"tmux-session-launcher/internal/fuzzyfinder"
+	"tmux-session-launcher/internal/mode"
+	"tmux-session-launcher/pkg/util"
+
+	"github.com/rodaine/table"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatTSV   = "tsv"
+)
+
+// HandlerList prints the picker entries without starting fzf or the RPC server
+func HandlerList(ctx context.Context, cmd *cli.Command) error {
+	m := mode.Mode(cmd.String("mode"))
+	if !mode.IsValid(m) {
+		return fmt.Errorf("invalid mode: %s", m)
+	}
+
+	entries := fuzzyfinder.GetEntries(ctx, m)
+
+	switch cmd.String("format") {
+	case FormatTable:
+		return writeTable(os.Stdout, entries)
+	case FormatJSON:
+		return writeJSON(os.Stdout, entries)
+	case FormatTSV:
+		return writeTSV(os.Stdout, entries)
+	default:
+		return fmt.Errorf("invalid format: %s", cmd.String("format"))
+	}
+}
+
+func writeTable(w io.Writer, entries []fuzzyfinder.Entry) error {
+	tbl := table.New("category", "name", "path", "id").WithWriter(w)
+	for _, e := range entries {
+		name := e.Name
+		if e.Current {
+			name = fmt.Sprintf("[%s]", name)
+		}
+
+		tbl.AddRow(e.Category, name, util.TruncateHomePath(e.Path), e.ID)
+	}
+	tbl.Print()
+
+	return nil
+}
+
+func writeJSON(w io.Writer, entries []fuzzyfinder.Entry) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(entries)
+}
+
+// writeTSV prints one entry per line: category, id, name, path, current
+func writeTSV(w io.Writer, entries []fuzzyfinder.Entry) error {
+	for _, e := range entries {
+		fields := []string{e.Category, e.ID, e.Name, e.Path, strconv.FormatBool(e.Current)}
+		for i, f := range fields {
+			fields[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(f)
+		}
+
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -8,11 +8,13 @@ const (
 	ModeAll       Mode = "all"
 	ModeSession   Mode = "session"
 	ModeDirectory Mode = "directory"
+	ModeWindow    Mode = "window"
+	ModePane      Mode = "pane"
 )
 
 var mu sync.Mutex
 var modeCurrent = ModeAll
-var Modes = []Mode{ModeAll, ModeSession, ModeDirectory}
+var Modes = []Mode{ModeAll, ModeSession, ModeDirectory, ModeWindow, ModePane}
 
 func Get() Mode {
 	return modeCurrent
@@ -59,6 +61,31 @@ func Prev() Mode {
 	return modeCurrent
 }
 
+// Register appends m to Modes unless it is already there.
+func Register(m Mode) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, mode := range Modes {
+		if m == mode {
+			return
+		}
+	}
+
+	Modes = append(Modes, m)
+}
+
+// IsValid reports whether m is one of Modes.
+func IsValid(m Mode) bool {
+	for _, mode := range Modes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (m Mode) String() string {
 	return string(m)
 }
@@ -0,0 +1,73 @@
+package naming
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"tmux-session-launcher/internal/config"
+)
+
+// Data holds the placeholders available to session name templates
+type Data struct {
+	// Base is the directory's own name
+	Base string
+	// Parent is the name of the directory containing it
+	Parent string
+	// GitBranch is the checked out branch, the short commit when detached, empty outside git
+	GitBranch string
+	// Label is the path relative to the parent of the configured directory entry, as shown
+	// in the picker, e.g. src/api for ~/src/api under the ~/src entry
+	Label string
+}
+
+func newData(cfg *config.Config, path string) Data {
+	data := Data{
+		Base:      filepath.Base(path),
+		Parent:    filepath.Base(filepath.Dir(path)),
+		GitBranch: gitBranch(path),
+		Label:     filepath.Base(path),
+	}
+
+	if dir := cfg.DirectoryFor(path); dir != nil {
+		root := config.ExpandPath(dir.Path)
+		if rel, err := filepath.Rel(filepath.Dir(root), path); err == nil {
+			data.Label = rel
+		}
+	}
+
+	return data
+}
+
+// gitBranch reads HEAD directly, it is called for every new session and git may not be installed
+func gitBranch(path string) string {
+	gitDir := filepath.Join(path, ".git")
+
+	// worktrees and submodules have a .git file pointing at the real directory
+	if content, err := os.ReadFile(gitDir); err == nil {
+		target, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir: ")
+		if !ok {
+			return ""
+		}
+
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(path, target)
+		}
+		gitDir = target
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+
+	ref := strings.TrimSpace(string(head))
+	if branch, ok := strings.CutPrefix(ref, "ref: refs/heads/"); ok {
+		return branch
+	}
+
+	if len(ref) > 7 {
+		return ref[:7]
+	}
+
+	return ref
+}
@@ -0,0 +1,139 @@
+package naming
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/internal/tmux"
+
+	"emperror.dev/errors"
+)
+
+const (
+	// DefaultSessionName is used when neither the directory entry nor the config set a template
+	DefaultSessionName = "{{.Base}}"
+	// DefaultWindowName is used for windows opened on a directory when the config sets no template
+	DefaultWindowName = "{{.Base}}"
+
+	// maxSuffix bounds the search for a free name with the suffix strategy
+	maxSuffix = 100
+)
+
+// SessionName returns the session name to use for path. A name already taken by a session
+// of another directory is disambiguated according to the configured strategy.
+func SessionName(ctx context.Context, cfg *config.Config, path string) (string, error) {
+	path = filepath.Clean(path)
+
+	name, err := Render(cfg, path)
+	if err != nil {
+		return "", err
+	}
+
+	for _, candidate := range candidates(name, path, cfg.SessionCollision) {
+		free, err := isFreeFor(ctx, candidate, path)
+		if err != nil {
+			return "", err
+		}
+
+		if free {
+			return candidate, nil
+		}
+	}
+
+	return "", errors.Errorf("no free session name for %s", path)
+}
+
+// Render expands the session name template configured for path, without checking for collisions
+func Render(cfg *config.Config, path string) (string, error) {
+	text := DefaultSessionName
+	if cfg.SessionName != "" {
+		text = cfg.SessionName
+	}
+	if dir := cfg.DirectoryFor(path); dir != nil && dir.SessionName != "" {
+		text = dir.SessionName
+	}
+
+	name, err := execute("session name", text, newData(cfg, path))
+	if err != nil {
+		return "", err
+	}
+
+	sanitized := tmux.SanitizeSessionName(name)
+	if sanitized == "" {
+		return "", errors.Errorf("session name template %q renders empty for %s", text, path)
+	}
+
+	return sanitized, nil
+}
+
+// WindowName expands the window name template for a window opened on path
+func WindowName(cfg *config.Config, path string) (string, error) {
+	path = filepath.Clean(path)
+
+	text := DefaultWindowName
+	if cfg.WindowName != "" {
+		text = cfg.WindowName
+	}
+
+	name, err := execute("window name", text, newData(cfg, path))
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		return "", errors.Errorf("window name template %q renders empty for %s", text, path)
+	}
+
+	return name, nil
+}
+
+func execute(kind, text string, data Data) (string, error) {
+	tmpl, err := template.New(kind).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", errors.WrapIff(err, "invalid %s template", kind)
+	}
+
+	var name strings.Builder
+	if err := tmpl.Execute(&name, data); err != nil {
+		return "", errors.WrapIff(err, "failed to render %s", kind)
+	}
+
+	return strings.TrimSpace(name.String()), nil
+}
+
+// isFreeFor reports whether name is unused or already used by the session of path
+func isFreeFor(ctx context.Context, name, path string) (bool, error) {
+	root, err := tmux.SessionRootPath(ctx, name)
+	if err != nil {
+		if errors.Is(err, tmux.ErrSessionNotFound) || errors.Is(err, tmux.ErrTmuxNotRunning) {
+			return true, nil
+		}
+
+		return false, errors.WrapIff(err, "failed to check session: %s", name)
+	}
+
+	return filepath.Clean(root) == filepath.Clean(path), nil
+}
+
+func candidates(name, path, strategy string) []string {
+	result := []string{name}
+
+	if strategy == "" || strategy == config.CollisionParent {
+		// api, personal/api, work/personal/api, ...
+		prefix := name
+		for dir := filepath.Dir(path); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+			prefix = tmux.BuildSessionNameFromPath(dir) + "/" + prefix
+			result = append(result, prefix)
+		}
+	}
+
+	// also the fallback once the parents run out
+	for i := 2; i <= maxSuffix; i++ {
+		result = append(result, fmt.Sprintf("%s-%d", name, i))
+	}
+
+	return result
+}
@@ -0,0 +1,86 @@
+package popup
+
+import (
+	"context"
+	"os"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/internal/fzf"
+	"tmux-session-launcher/internal/tmux"
+	"tmux-session-launcher/pkg/logger"
+	"tmux-session-launcher/pkg/util"
+
+	"emperror.dev/errors"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	FlagPopup = "popup"
+
+	// EnvInPopup marks the process running inside the popup so it does not open another one
+	EnvInPopup = "TMUX_SESSION_LAUNCHER_IN_POPUP"
+	// EnvOriginPane is the pane the launcher was opened from, the one under the popup
+	EnvOriginPane = "TMUX_SESSION_LAUNCHER_ORIGIN_PANE"
+)
+
+// forwardedEnv are variables the launcher and its fzf read; the popup otherwise gets the tmux session environment
+var forwardedEnv = []string{
+	"XDG_CONFIG_HOME",
+	"XDG_STATE_HOME",
+	"XDG_RUNTIME_DIR",
+	"VERBOSITY_LEVEL",
+	"NO_COLOR",
+	fzf.EnvLauncherOptions,
+	"FZF_DEFAULT_OPTS",
+}
+
+// WithPopup re-runs the command inside a tmux popup when --popup is set. Outside tmux, or
+// once inside the popup, the action runs inline.
+func WithPopup(next cli.ActionFunc) cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		if !cmd.Bool(FlagPopup) || !tmux.IsInSession() || os.Getenv(EnvInPopup) != "" {
+			return next(ctx, cmd)
+		}
+
+		return run(ctx)
+	}
+}
+
+func run(ctx context.Context) error {
+	log := logger.WithPrefix("popup.run")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return errors.WrapIf(err, "failed to load config")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get executable path")
+	}
+
+	env := map[string]string{EnvInPopup: "1"}
+	if pane, err := tmux.GetCurrentPane(ctx); err == nil {
+		env[EnvOriginPane] = pane
+	}
+	for _, name := range forwardedEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			env[name] = value
+		}
+	}
+
+	cwd, _ := os.Getwd()
+	popup := cfg.PopupOptions()
+
+	// the same arguments again, the marker turns --popup into a no-op
+	command := util.ShellJoin(append([]string{exe}, os.Args[1:]...))
+	log.Debugf("Running in popup: %s", command)
+
+	return tmux.DisplayPopup(ctx, command, tmux.PopupOptions{
+		Width:  popup.Width,
+		Height: popup.Height,
+		Border: popup.Border,
+		Title:  popup.Title,
+		Path:   cwd,
+		Env:    env,
+	})
+}
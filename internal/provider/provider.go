@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/internal/mode"
+	"tmux-session-launcher/pkg/util"
+
+	"emperror.dev/errors"
+)
+
+// Entry is a row printed by a provider command
+type Entry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+var (
+	mu sync.Mutex
+	// listed holds the rows of the last listing of each provider by id, previews and actions on
+	// those rows must not run the command again
+	listed = make(map[string]map[string]Entry)
+)
+
+// RegisterModes adds a mode for every configured provider
+func RegisterModes(cfg *config.Config) {
+	for _, p := range cfg.Providers {
+		mode.Register(mode.Mode(p.Name))
+	}
+}
+
+// List runs the provider command and parses its rows
+func List(ctx context.Context, p config.ProviderConfig) ([]Entry, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.Command)
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, errors.WrapIff(err, "provider %s failed: %s", p.Name, strings.TrimSpace(stderr.String()))
+	}
+
+	var entries []Entry
+	if p.Format == config.ProviderFormatJSON {
+		if err := json.Unmarshal(output, &entries); err != nil {
+			return nil, errors.WrapIff(err, "provider %s printed invalid json", p.Name)
+		}
+	} else {
+		entries = parseTSV(output)
+	}
+
+	valid := entries[:0]
+	for _, e := range entries {
+		if e.ID == "" {
+			continue
+		}
+
+		if e.Name == "" {
+			e.Name = e.ID
+		}
+
+		if e.Path != "" {
+			e.Path = config.ExpandPath(e.Path)
+		}
+
+		valid = append(valid, e)
+	}
+
+	byID := make(map[string]Entry, len(valid))
+	for _, e := range valid {
+		byID[e.ID] = e
+	}
+
+	mu.Lock()
+	listed[p.Name] = byID
+	mu.Unlock()
+
+	return valid, nil
+}
+
+// Find returns the row with the given id from the last listing, and only runs the provider
+// command when the row was never listed
+func Find(ctx context.Context, p config.ProviderConfig, id string) (*Entry, error) {
+	mu.Lock()
+	e, ok := listed[p.Name][id]
+	mu.Unlock()
+
+	if ok {
+		return &e, nil
+	}
+
+	entries, err := List(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if e.ID == id {
+			return &e, nil
+		}
+	}
+
+	return nil, errors.Errorf("provider %s no longer lists: %s", p.Name, id)
+}
+
+// RunCommand renders the provider run template for the entry and runs it in our terminal
+func RunCommand(ctx context.Context, p config.ProviderConfig, entry Entry) error {
+	command, err := util.ShellTemplate(p.Name, p.Run, map[string]string{
+		"ID":   entry.ID,
+		"Name": entry.Name,
+		"Path": entry.Path,
+	})
+	if err != nil {
+		return errors.WrapIff(err, "failed to render run template of provider %s", p.Name)
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if info, err := os.Stat(entry.Path); err == nil && info.IsDir() {
+		cmd.Dir = entry.Path
+	}
+
+	return cmd.Run()
+}
+
+// parseTSV reads "id<TAB>name<TAB>path" lines, trailing fields are optional
+func parseTSV(output []byte) []Entry {
+	var entries []Entry
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+
+		var e Entry
+		e.ID = strings.TrimSpace(fields[0])
+		if len(fields) > 1 {
+			e.Name = strings.TrimSpace(fields[1])
+		}
+		if len(fields) > 2 {
+			e.Path = strings.TrimSpace(fields[2])
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries
+}
@@ -0,0 +1,19 @@
+package rpc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// NewSockAddress returns a socket path unique to this process, under $XDG_RUNTIME_DIR when set.
+func NewSockAddress() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	name := fmt.Sprintf("tmux-session-launcher-%d-%d.sock", os.Getuid(), os.Getpid())
+
+	return filepath.Join(dir, name)
+}
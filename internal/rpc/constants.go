@@ -1,6 +1,12 @@
 package rpc
 
-const SockAddress = "/tmp/tmux-session-launcher.sock"
+// EnvSockAddress passes the launcher's socket path down to the action subcommands spawned by fzf.
+const EnvSockAddress = "TMUX_SESSION_LAUNCHER_SOCK"
+
+// SelectionSeparator delimits the fields of the picker rows, and so the category and id of the
+// selections fzf passes to the action subcommands. Names and paths are free text where a
+// printable separator would shift the fields.
+const SelectionSeparator = "\t"
 
 // JSON-RPC method names following RPC conventions
 const (
@@ -8,5 +14,9 @@ const (
 	MethodModePrev       = "mode.previous"
 	MethodModeGet        = "mode.get"
 	MethodContentGet     = "content.get"
+	MethodContentPreview = "content.preview"
 	MethodLauncherOpenIn = "launcher.openIn"
+	MethodLauncherRename = "launcher.rename"
+	MethodLauncherKill   = "launcher.kill"
+	MethodLauncherAction = "launcher.runAction"
 )
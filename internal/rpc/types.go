@@ -4,8 +4,34 @@ package rpc
 type EmptyParams struct{}
 
 type OpenInParams struct {
-	Category  string `json:"category"`
-	Path      string `json:"path"`
+	Category string `json:"category"`
+	Path     string `json:"path"`
+}
+
+type PreviewParams struct {
+	Category string `json:"category"`
+	ID       string `json:"id"`
+}
+
+type RenameParams struct {
+	Category string `json:"category"`
+	ID       string `json:"id"`
+}
+
+// RunActionParams names a custom action from the configuration and the row to run it on
+type RunActionParams struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	ID       string `json:"id"`
+}
+
+type Selection struct {
+	Category string `json:"category"`
+	ID       string `json:"id"`
+}
+
+type KillParams struct {
+	Selections []Selection `json:"selections"`
 }
 
 type ModeResponse struct {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"os"
+	"syscall"
 	"tmux-session-launcher/pkg/logger"
 
 	"emperror.dev/errors"
@@ -30,15 +31,20 @@ func (s *Server) Start(ctx context.Context) error {
 
 	logger.Infof("Starting JSON-RPC server at %s", s.Address)
 
-	// Remove existing socket file if it exists
+	// Remove existing socket file only if nobody is listening on it anymore
 	if _, err := os.Stat(s.Address); err == nil {
-		logger.Warnf("Socket file %s already exists, removing it", s.Address)
+		if conn, err := net.Dial("unix", s.Address); err == nil {
+			conn.Close()
+			return errors.Errorf("socket %s is in use by another launcher", s.Address)
+		}
+
+		logger.Warnf("Socket file %s is stale, removing it", s.Address)
 		if err := os.Remove(s.Address); err != nil {
-			return errors.Wrap(err, "failed to remove existing socket file")
+			return errors.Wrap(err, "failed to remove stale socket file")
 		}
 	}
 
-	listener, err := net.Listen("unix", s.Address)
+	listener, err := listenPrivate(s.Address)
 	if err != nil {
 		return errors.Wrap(err, "failed to start socket listener")
 	}
@@ -101,3 +107,12 @@ func (s *Server) Stop() error {
 func (s *Server) RegisterHandler(method string, handler jrpc2.Handler) {
 	s.assigner[method] = handler
 }
+
+// listenPrivate creates the socket under a umask that leaves other users no access, a chmod
+// after listening would leave a window in which they could connect
+func listenPrivate(address string) (net.Listener, error) {
+	umask := syscall.Umask(0077)
+	defer syscall.Umask(umask)
+
+	return net.Listen("unix", address)
+}
@@ -0,0 +1,164 @@
+package sessiontemplate
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/internal/tmux"
+	"tmux-session-launcher/pkg/glob"
+	"tmux-session-launcher/pkg/logger"
+
+	"emperror.dev/errors"
+)
+
+// Find returns the template for a directory: the one named by the closest directory entry,
+// otherwise the first template whose match globs cover the path. Nil if none applies.
+func Find(cfg *config.Config, path string) *config.TemplateConfig {
+	if dir := cfg.DirectoryFor(path); dir != nil && dir.Template != "" {
+		return cfg.FindTemplate(dir.Template)
+	}
+
+	for i, tmpl := range cfg.Templates {
+		for _, pattern := range tmpl.Match {
+			p, err := glob.Compile(strings.TrimPrefix(config.ExpandPath(pattern), "/"))
+			if err != nil {
+				continue
+			}
+
+			if p.Match(strings.TrimPrefix(path, "/")) {
+				return &cfg.Templates[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// Setup returns a tmux.SessionSetup applying the template configured for path, if any.
+func Setup(path string) tmux.SessionSetup {
+	return func(ctx context.Context, session *tmux.Session) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return errors.WrapIf(err, "failed to load configuration")
+		}
+
+		tmpl := Find(cfg, path)
+		if tmpl == nil {
+			return nil
+		}
+
+		return Apply(ctx, session, path, tmpl)
+	}
+}
+
+// Apply replaces the initial window of a new session with the template's windows and panes.
+func Apply(ctx context.Context, session *tmux.Session, path string, tmpl *config.TemplateConfig) error {
+	log := logger.WithPrefix("sessiontemplate.Apply")
+	log.Infof("Applying template %s to session %s", tmpl.Name, session.Name)
+
+	if len(tmpl.Windows) == 0 {
+		return nil
+	}
+
+	for key, value := range tmpl.Env {
+		if err := tmux.SessionSetEnvironment(ctx, session.ID, key, value); err != nil {
+			return errors.WrapIff(err, "failed to set environment: %s", key)
+		}
+	}
+
+	// new-session always opens a window, it is replaced once the template's are in place
+	initial, err := tmux.GetWindowsOf(ctx, session.ID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get initial window")
+	}
+
+	var first string
+	for _, window := range tmpl.Windows {
+		id, err := applyWindow(ctx, session, path, window)
+		if err != nil {
+			return err
+		}
+
+		if first == "" {
+			first = id
+		}
+	}
+
+	for _, w := range initial {
+		if err := tmux.WindowKill(ctx, w.ID); err != nil {
+			return errors.WrapIff(err, "failed to remove initial window: %s", w.ID)
+		}
+	}
+
+	if err := tmux.WindowsRenumber(ctx, session.ID); err != nil {
+		log.Warnf("Failed to renumber windows: %v", err)
+	}
+
+	return tmux.WindowActivate(ctx, first)
+}
+
+func applyWindow(ctx context.Context, session *tmux.Session, root string, window config.WindowConfig) (string, error) {
+	windowPath := resolvePath(root, window.Path)
+
+	panes := window.Panes
+	if len(panes) == 0 {
+		panes = []config.PaneConfig{{}}
+	}
+
+	id, paneID, err := tmux.WindowCreateWithOptions(ctx, tmux.WindowOptions{
+		Target:   session.ID + ":",
+		Name:     window.Name,
+		Path:     resolvePath(windowPath, panes[0].Path),
+		Env:      panes[0].Env,
+		Detached: true,
+	})
+	if err != nil {
+		return "", errors.WrapIff(err, "failed to create window: %s", window.Name)
+	}
+
+	paneIDs := []string{paneID}
+	for _, pane := range panes[1:] {
+		paneID, err := tmux.PaneCreateWithOptions(ctx, tmux.PaneOptions{
+			Target: paneIDs[len(paneIDs)-1],
+			Path:   resolvePath(windowPath, pane.Path),
+			Env:    pane.Env,
+		})
+		if err != nil {
+			return "", errors.WrapIff(err, "failed to create pane in window: %s", window.Name)
+		}
+
+		paneIDs = append(paneIDs, paneID)
+	}
+
+	if window.Layout != "" {
+		if err := tmux.WindowLayout(ctx, id, window.Layout); err != nil {
+			return "", errors.WrapIff(err, "failed to apply layout: %s", window.Layout)
+		}
+	}
+
+	for i, pane := range panes {
+		if pane.Command == "" {
+			continue
+		}
+
+		if err := tmux.SendKeys(ctx, paneIDs[i], pane.Command); err != nil {
+			return "", errors.WrapIff(err, "failed to start command: %s", pane.Command)
+		}
+	}
+
+	return id, nil
+}
+
+func resolvePath(base, path string) string {
+	if path == "" {
+		return base
+	}
+
+	path = config.ExpandPath(path)
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(base, path)
+}
@@ -10,10 +10,13 @@ const (
 	ErrTmuxNotRunning  = errors.Sentinel("tmux server is not running")
 	ErrSessionExists   = errors.Sentinel("tmux session already exists")
 	ErrSessionNotFound = errors.Sentinel("tmux session not found")
+
+	ErrSessionPathMismatch = errors.Sentinel("tmux session already exists for another directory")
+	ErrInvalidSessionName  = errors.Sentinel("tmux session name must not be empty or contain ':' or '.'")
 )
 
 func isTmuxNotRunningErr(output string) bool {
-	return strings.HasPrefix(output, "no server running")
+	return strings.HasPrefix(output, "no server running") || strings.HasPrefix(output, "error connecting to")
 }
 
 func isSessionNotFoundErr(output string) bool {
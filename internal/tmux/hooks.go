@@ -0,0 +1,51 @@
+package tmux
+
+import (
+	"context"
+	"tmux-session-launcher/pkg/logger"
+)
+
+// AttachHook runs once a client is attached or switched to session, previous is the session it
+// left, empty when unknown
+type AttachHook func(ctx context.Context, previous string, session *Session)
+
+// RenameHook runs once a session was renamed
+type RenameHook func(ctx context.Context, oldName, newName string)
+
+var (
+	attachHooks []AttachHook
+	renameHooks []RenameHook
+)
+
+// OnAttach registers a hook run on every attach or switch done through this package.
+func OnAttach(hook AttachHook) {
+	attachHooks = append(attachHooks, hook)
+}
+
+// OnRename registers a hook run on every session rename done through this package.
+func OnRename(hook RenameHook) {
+	renameHooks = append(renameHooks, hook)
+}
+
+// notifyAttach runs the attach hooks, before attaching when the process is replaced by tmux
+func notifyAttach(ctx context.Context, previous, id string) {
+	if len(attachHooks) == 0 {
+		return
+	}
+
+	session, err := GetSession(ctx, id)
+	if err != nil {
+		logger.Warnf("Failed to resolve attached session %s: %v", id, err)
+		return
+	}
+
+	for _, hook := range attachHooks {
+		hook(ctx, previous, session)
+	}
+}
+
+func notifyRename(ctx context.Context, oldName, newName string) {
+	for _, hook := range renameHooks {
+		hook(ctx, oldName, newName)
+	}
+}
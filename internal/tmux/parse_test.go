@@ -0,0 +1,47 @@
+package tmux
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitFormat(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{`$1|api|/src/api`, []string{"$1", "api", "/src/api"}},
+		{`$1|a\|b|/src/p\|q\ r`, []string{"$1", "a|b", "/src/p|q r"}},
+		{`$1|back\\slash|`, []string{"$1", `back\slash`, ""}},
+		{``, []string{""}},
+	}
+
+	for _, tt := range tests {
+		if got := splitFormat(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitFormat(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	window, err := parseWindow(`@3|logs\|tail|$1|my\ proj|2|/srv/app`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Window{
+		ID:          "@3",
+		Name:        "logs|tail",
+		SessionID:   "$1",
+		SessionName: "my proj",
+		Index:       "2",
+		Path:        "/srv/app",
+	}
+	if *window != want {
+		t.Errorf("parseWindow() = %+v, want %+v", *window, want)
+	}
+
+	if _, err := parseWindow(`@3|logs|tail|$1|proj|2|/srv/app`); err == nil {
+		t.Error("parseWindow() accepted an unescaped separator")
+	}
+}
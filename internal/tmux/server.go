@@ -0,0 +1,101 @@
+package tmux
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Server selects the tmux server commands talk to, the zero value is the one tmux picks itself:
+// the server of the current client, or the default one.
+type Server struct {
+	Name   string // socket name, as with tmux -L
+	Socket string // socket path, as with tmux -S; wins over Name
+}
+
+type serverKey struct{}
+
+var defaultServer Server
+
+// SetDefaultServer picks the server for commands whose context does not carry one.
+func SetDefaultServer(server Server) {
+	defaultServer = server
+}
+
+// DefaultServer returns the server set with SetDefaultServer.
+func DefaultServer() Server {
+	return defaultServer
+}
+
+// WithServer returns a context whose tmux commands go to server.
+func WithServer(ctx context.Context, server Server) context.Context {
+	return context.WithValue(ctx, serverKey{}, server)
+}
+
+// ParseServer reads a socket path, anything with a slash, or a socket name.
+func ParseServer(spec string) Server {
+	if strings.Contains(spec, "/") {
+		return Server{Socket: spec}
+	}
+
+	return Server{Name: spec}
+}
+
+// Label names the server in listings.
+func (s Server) Label() string {
+	switch {
+	case s.Socket != "":
+		return filepath.Base(s.Socket)
+	case s.Name != "":
+		return s.Name
+	default:
+		return "default"
+	}
+}
+
+func (s Server) args() []string {
+	switch {
+	case s.Socket != "":
+		return []string{"-S", s.Socket}
+	case s.Name != "":
+		return []string{"-L", s.Name}
+	default:
+		return nil
+	}
+}
+
+func serverOf(ctx context.Context) Server {
+	if server, ok := ctx.Value(serverKey{}).(Server); ok {
+		return server
+	}
+
+	return defaultServer
+}
+
+// command builds a tmux command for the server of ctx.
+func command(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, "tmux", append(serverOf(ctx).args(), args...)...)
+}
+
+// isCurrentServer reports whether we run inside a client of the server of ctx.
+func isCurrentServer(ctx context.Context) bool {
+	if !IsInSession() {
+		return false
+	}
+
+	server := serverOf(ctx)
+	if server == (Server{}) {
+		// tmux itself talks to the server in $TMUX
+		return true
+	}
+
+	output, err := command(ctx, "display-message", "-p", "#{socket_path}").Output()
+	if err != nil {
+		return false
+	}
+
+	socket, _, _ := strings.Cut(os.Getenv("TMUX"), ",")
+	return strings.TrimSpace(string(output)) == socket
+}
@@ -5,15 +5,24 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
 	"tmux-session-launcher/pkg/util"
+	"unicode"
+	"unicode/utf8"
 
 	"emperror.dev/errors"
 )
 
 const (
-	sessionFormat = "#{session_id}|#{session_name}|#{session_path}"
+	// optionLauncherPath is the session user option holding the directory a session was created for
+	optionLauncherPath = "@launcher_path"
+
+	// names, commands and paths are free text, q: escapes the separator in them with a backslash
+	sessionFormat = "#{session_id}|#{q:session_name}|#{q:session_path}"
+	windowFormat  = "#{window_id}|#{q:window_name}|#{session_id}|#{q:session_name}|#{window_index}|#{q:pane_current_path}"
+	paneFormat    = "#{pane_id}|#{q:pane_current_command}|#{session_id}|#{q:session_name}|#{window_index}.#{pane_index}|#{q:pane_current_path}"
 )
 
 type Session struct {
@@ -23,8 +32,26 @@ type Session struct {
 	Current bool
 }
 
+type Window struct {
+	ID          string
+	Name        string
+	SessionID   string
+	SessionName string
+	Index       string
+	Path        string
+}
+
+type Pane struct {
+	ID          string
+	Command     string
+	SessionID   string
+	SessionName string
+	Index       string // window_index.pane_index
+	Path        string
+}
+
 func IsRunning(ctx context.Context) bool {
-	cmd := exec.CommandContext(ctx, "tmux", "info")
+	cmd := command(ctx, "info")
 	err := cmd.Run()
 	return err == nil
 }
@@ -35,30 +62,57 @@ func IsInSession() bool {
 }
 
 func GetCurrentSession(ctx context.Context) (*Session, error) {
-	cmd := exec.CommandContext(
-		ctx,
-		"tmux",
-		"display-message",
-		"-p",
-		sessionFormat,
-	)
+	// without a client tmux would answer with the most recently used session
+	if !isCurrentServer(ctx) {
+		return nil, ErrSessionNotFound
+	}
+
+	return GetSession(ctx, "")
+}
+
+// GetCurrentPane returns the ID of the active pane of the current client.
+func GetCurrentPane(ctx context.Context) (string, error) {
+	if !isCurrentServer(ctx) {
+		return "", ErrSessionNotFound
+	}
+
+	cmd := command(ctx, "display-message", "-p", "#{pane_id}")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if err := handleTmuxError(string(output)); err != nil {
+			return "", err
+		}
+
+		return "", errors.WrapIff(err, "failed to get current pane: %s", output)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetSession returns the session matching target, or the current one when target is empty.
+func GetSession(ctx context.Context, target string) (*Session, error) {
+	args := []string{"display-message", "-p"}
+	if target != "" {
+		args = append(args, "-t", target)
+	}
+	args = append(args, sessionFormat)
+
+	cmd := command(ctx, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		if err := handleTmuxError(string(output)); err != nil {
 			return nil, err
 		}
 
-		return nil, errors.WrapIff(err, "failed to get current session: %s", output)
+		return nil, errors.WrapIff(err, "failed to get session: %s", output)
 	}
 
 	return parseSession(strings.TrimSpace(string(output)))
 }
 
 func GetSessions(ctx context.Context) ([]Session, error) {
-	cmd := exec.CommandContext(
+	cmd := command(
 		ctx,
-		"tmux",
-		// "-L", "test",
 		"list-sessions",
 		"-F", sessionFormat,
 	)
@@ -74,6 +128,7 @@ func GetSessions(ctx context.Context) ([]Session, error) {
 
 	currentSession, _ := GetCurrentSession(ctx)
 	if currentSession != nil {
+		currentSession.Current = true
 		sessions = append(sessions, *currentSession)
 	}
 
@@ -94,14 +149,82 @@ func GetSessions(ctx context.Context) ([]Session, error) {
 	return sessions, nil
 }
 
+// GetWindows lists the windows of every session.
+func GetWindows(ctx context.Context) ([]Window, error) {
+	return listWindows(ctx, "-a")
+}
+
+// GetWindowsOf lists the windows of a single session.
+func GetWindowsOf(ctx context.Context, session string) ([]Window, error) {
+	return listWindows(ctx, "-t", session)
+}
+
+func listWindows(ctx context.Context, scope ...string) ([]Window, error) {
+	args := append([]string{"list-windows"}, scope...)
+	args = append(args, "-F", windowFormat)
+
+	cmd := command(ctx, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if err := handleTmuxError(string(output)); err != nil {
+			return []Window{}, err
+		}
+
+		return []Window{}, errors.WrapIff(err, "failed to list windows: %s", output)
+	}
+
+	var windows []Window
+	for line := range strings.SplitSeq(string(output), "\n") {
+		window, err := parseWindow(line)
+		if err != nil {
+			continue
+		}
+
+		windows = append(windows, *window)
+	}
+
+	return windows, nil
+}
+
+// GetPanes lists the panes of every window in every session.
+func GetPanes(ctx context.Context) ([]Pane, error) {
+	cmd := command(
+		ctx,
+		"list-panes",
+		"-a",
+		"-F", paneFormat,
+	)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if err := handleTmuxError(string(output)); err != nil {
+			return []Pane{}, err
+		}
+
+		return []Pane{}, errors.WrapIff(err, "failed to list panes: %s", output)
+	}
+
+	var panes []Pane
+	for line := range strings.SplitSeq(string(output), "\n") {
+		pane, err := parsePane(line)
+		if err != nil {
+			continue
+		}
+
+		panes = append(panes, *pane)
+	}
+
+	return panes, nil
+}
+
 func SessionCreate(ctx context.Context, name, path string) (*Session, error) {
-	cmd := exec.CommandContext(
+	cmd := command(
 		ctx,
-		"tmux",
 		"new-session",
 		"-d",       // detached
 		"-s", name, // session name
 		"-c", path, // start directory
+		"-P", "-F", sessionFormat, // print the created session
 	)
 
 	output, err := cmd.CombinedOutput()
@@ -113,24 +236,53 @@ func SessionCreate(ctx context.Context, name, path string) (*Session, error) {
 		return nil, errors.WrapIff(err, "failed to create: %s", output)
 	}
 
-	cmd = exec.CommandContext(
-		ctx,
-		"tmux",
-		"list-sessions",
-		"-f", "#{==:#{session_name},"+name+"}",
-		"-F", sessionFormat,
-	)
+	session, err := parseSession(strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil, err
+	}
+
+	// session_path follows the working directory, the option keeps the root it was created for
+	if err := run(ctx, "set-option", "-t", session.ID, optionLauncherPath, path); err != nil {
+		return nil, errors.WrapIff(err, "failed to record root path: %s", name)
+	}
+
+	return session, nil
+}
+
+// SessionRootPath returns the directory the named session was created for, falling back to
+// its session_path for sessions not created by the launcher.
+func SessionRootPath(ctx context.Context, name string) (string, error) {
+	// an exact target, names may hold characters that are special in formats
+	if err := run(ctx, "has-session", "-t", "="+name); err != nil {
+		return "", err
+	}
 
-	output, err = cmd.CombinedOutput()
+	// options take a pane target, hence the trailing colon
+	target := "=" + name + ":"
+
+	output, err := command(ctx, "show-options", "-q", "-v", "-t", target, optionLauncherPath).CombinedOutput()
 	if err != nil {
 		if err := handleTmuxError(string(output)); err != nil {
-			return nil, err
+			return "", err
 		}
 
-		return nil, errors.WrapIff(err, "failed to get created: %s", output)
+		return "", errors.WrapIff(err, "failed to get root path: %s", output)
 	}
 
-	return parseSession(strings.TrimSpace(string(output)))
+	if root := strings.TrimSpace(string(output)); root != "" {
+		return root, nil
+	}
+
+	output, err = command(ctx, "display-message", "-p", "-t", target, "#{session_path}").CombinedOutput()
+	if err != nil {
+		if err := handleTmuxError(string(output)); err != nil {
+			return "", err
+		}
+
+		return "", errors.WrapIff(err, "failed to get session path: %s", output)
+	}
+
+	return strings.TrimSpace(string(output)), nil
 }
 
 func SessionAttach(ctx context.Context, id string) error {
@@ -142,13 +294,32 @@ func SessionAttach(ctx context.Context, id string) error {
 			return err
 		}
 
+		notifyAttach(ctx, "", id)
+
 		// Replace current process with tmux
-		return syscall.Exec(tmuxPath, []string{"tmux", "attach-session", "-t", id}, os.Environ())
+		args := append([]string{"tmux"}, serverOf(ctx).args()...)
+		args = append(args, "attach-session", "-t", id)
+		return syscall.Exec(tmuxPath, args, os.Environ())
+	}
+
+	if !isCurrentServer(ctx) {
+		notifyAttach(ctx, "", id)
+
+		// a client cannot switch to a session of another server, so it is replaced by one that can
+		attach := append([]string{"tmux"}, serverOf(ctx).args()...)
+		attach = append(attach, "attach-session", "-t", id)
+
+		return run(WithServer(ctx, Server{}), "detach-client", "-E", util.ShellJoin(attach))
 	}
 
-	cmd := exec.CommandContext(
+	// remembered so the session being left can be toggled back to
+	previous := ""
+	if current, err := GetCurrentSession(ctx); err == nil {
+		previous = current.Name
+	}
+
+	cmd := command(
 		ctx,
-		"tmux",
 		"switch-client",
 		"-t", id,
 	)
@@ -162,15 +333,44 @@ func SessionAttach(ctx context.Context, id string) error {
 		return errors.WrapIff(err, "failed to attach: %s", output)
 	}
 
+	notifyAttach(ctx, previous, id)
+
 	return nil
 }
 
-func SessionCreateOrAttach(ctx context.Context, name, path string) (*Session, error) {
+// SessionSetup prepares a freshly created session before it is attached.
+type SessionSetup func(ctx context.Context, session *Session) error
+
+// SessionCreateOrAttach attaches to the named session, creating it first if needed. The setups
+// only run when the session was created by this call, which is killed again if one fails.
+func SessionCreateOrAttach(ctx context.Context, name, path string, setups ...SessionSetup) (*Session, error) {
 	session, err := SessionCreate(ctx, name, path)
 	if err != nil {
 		if !errors.Is(err, ErrSessionExists) {
 			return nil, errors.WrapIff(err, "failed to create: %s", name)
 		}
+
+		root, err := SessionRootPath(ctx, name)
+		if err != nil {
+			return nil, errors.WrapIff(err, "failed to check existing: %s", name)
+		}
+
+		if filepath.Clean(root) != filepath.Clean(path) {
+			return nil, errors.WithDetails(ErrSessionPathMismatch, "session", name, "path", root)
+		}
+	}
+
+	if session != nil {
+		for _, setup := range setups {
+			if err := setup(ctx, session); err != nil {
+				// a half-built session would be attached as is by the next open
+				if errKill := SessionKill(ctx, session.ID); errKill != nil {
+					err = errors.Combine(err, errors.WrapIf(errKill, "failed to kill the half-built session"))
+				}
+
+				return nil, errors.WrapIff(err, "failed to set up: %s", name)
+			}
+		}
 	}
 
 	err = SessionAttach(ctx, name)
@@ -181,60 +381,424 @@ func SessionCreateOrAttach(ctx context.Context, name, path string) (*Session, er
 	return session, nil
 }
 
-func PaneCreate(ctx context.Context, path string) error {
-	cmd := exec.CommandContext(
+// WindowSelect makes the window current in its session and switches to that session.
+func WindowSelect(ctx context.Context, id string) error {
+	if err := WindowActivate(ctx, id); err != nil {
+		return err
+	}
+
+	return attachSessionOf(ctx, id)
+}
+
+// WindowActivate makes the window current in its session without switching the client.
+func WindowActivate(ctx context.Context, id string) error {
+	if err := run(ctx, "select-window", "-t", id); err != nil {
+		return errors.WrapIff(err, "failed to select window: %s", id)
+	}
+
+	return nil
+}
+
+// PaneSelect makes the pane and its window current and switches to their session.
+func PaneSelect(ctx context.Context, id string) error {
+	if err := run(ctx, "select-window", "-t", id); err != nil {
+		return errors.WrapIff(err, "failed to select window of pane: %s", id)
+	}
+
+	if err := run(ctx, "select-pane", "-t", id); err != nil {
+		return errors.WrapIff(err, "failed to select pane: %s", id)
+	}
+
+	return attachSessionOf(ctx, id)
+}
+
+func attachSessionOf(ctx context.Context, target string) error {
+	cmd := command(ctx, "display-message", "-p", "-t", target, "#{session_id}")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if err := handleTmuxError(string(output)); err != nil {
+			return err
+		}
+
+		return errors.WrapIff(err, "failed to get session of: %s", target)
+	}
+
+	return SessionAttach(ctx, strings.TrimSpace(string(output)))
+}
+
+// SessionExists reports whether a session with exactly the given name exists.
+func SessionExists(ctx context.Context, name string) bool {
+	cmd := command(ctx, "has-session", "-t", "="+name)
+	return cmd.Run() == nil
+}
+
+func SessionRename(ctx context.Context, id, name string) error {
+	if !IsValidSessionName(name) {
+		return ErrInvalidSessionName
+	}
+
+	if SessionExists(ctx, name) {
+		return ErrSessionExists
+	}
+
+	session, err := GetSession(ctx, id)
+	if err != nil {
+		return errors.WrapIff(err, "failed to get session: %s", id)
+	}
+
+	cmd := command(
 		ctx,
-		"tmux",
-		"split-window",
-		"-c", path, // start directory
+		"rename-session",
+		"-t", id,
+		name,
 	)
 
 	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if err := handleTmuxError(string(output)); err != nil {
+			return err
+		}
 
+		return errors.WrapIff(err, "failed to rename: %s", output)
+	}
+
+	notifyRename(ctx, session.Name, name)
+
+	return nil
+}
+
+func SessionKill(ctx context.Context, id string) error {
+	cmd := command(
+		ctx,
+		"kill-session",
+		"-t", id,
+	)
+
+	output, err := cmd.CombinedOutput()
 	if err != nil {
 		if err := handleTmuxError(string(output)); err != nil {
 			return err
 		}
 
-		return errors.WrapIff(err, "failed to create pane: %s", output)
+		return errors.WrapIff(err, "failed to kill: %s", output)
 	}
 
 	return nil
 }
 
+// IsValidSessionName reports whether tmux accepts name as a session name as-is.
+func IsValidSessionName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, ":.")
+}
+
+func PaneCreate(ctx context.Context, path string) error {
+	_, err := PaneCreateWithOptions(ctx, PaneOptions{Path: path})
+	return err
+}
+
 func WindowCreate(ctx context.Context, path string) error {
-	cmd := exec.CommandContext(
+	_, _, err := WindowCreateWithOptions(ctx, WindowOptions{Path: path})
+	return err
+}
+
+// SplitDirection is where a new pane goes relative to the split one
+type SplitDirection string
+
+const (
+	// SplitHorizontal puts the new pane beside the split one
+	SplitHorizontal SplitDirection = "horizontal"
+	// SplitVertical puts the new pane below the split one
+	SplitVertical SplitDirection = "vertical"
+)
+
+// PaneOptions configures a split, zero values fall back to tmux defaults.
+type PaneOptions struct {
+	Target    string // pane to split
+	Path      string // start directory
+	Env       map[string]string
+	Command   string // shell command to run instead of the default shell
+	Direction SplitDirection
+}
+
+// WindowOptions configures a new window, zero values fall back to tmux defaults.
+type WindowOptions struct {
+	Target   string // session (or window index) to create the window in
+	Name     string
+	Path     string // start directory
+	Env      map[string]string
+	Detached bool   // keep the current window selected
+	Command  string // shell command to run instead of the default shell
+}
+
+// PopupOptions configures a popup, zero values fall back to tmux defaults.
+type PopupOptions struct {
+	Width  string // columns or a percentage
+	Height string // lines or a percentage
+	Border string // popup-border-lines style
+	Title  string
+	Path   string // start directory
+	Env    map[string]string
+	// KeepOpen leaves the popup up after the command exits, until it is closed with a key
+	KeepOpen bool
+}
+
+// DisplayPopup runs command in a popup on the current client and waits until the popup is
+// closed. Cancelling ctx closes the popup instead of leaving it orphaned.
+func DisplayPopup(ctx context.Context, shellCommand string, opts PopupOptions) error {
+	args := []string{"display-popup"}
+	if !opts.KeepOpen {
+		args = append(args, "-E")
+	}
+	if opts.Width != "" {
+		args = append(args, "-w", opts.Width)
+	}
+	if opts.Height != "" {
+		args = append(args, "-h", opts.Height)
+	}
+	if opts.Border != "" {
+		args = append(args, "-b", opts.Border)
+	}
+	if opts.Title != "" {
+		args = append(args, "-T", opts.Title)
+	}
+	if opts.Path != "" {
+		args = append(args, "-d", opts.Path)
+	}
+	args = append(args, envArgs(opts.Env)...)
+	args = append(args, shellCommand)
+
+	// the popup belongs to the client we run in, whichever server the launcher targets
+	ctx = WithServer(ctx, Server{})
+
+	cmd := command(ctx, args...)
+	cmd.Cancel = func() error {
+		// killing our tmux client alone would leave the popup on screen
+		_ = command(context.WithoutCancel(ctx), "display-popup", "-C").Run()
+		return cmd.Process.Kill()
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if err := handleTmuxError(string(output)); err != nil {
+			return err
+		}
+
+		return errors.WrapIff(err, "failed to display popup: %s", output)
+	}
+
+	return nil
+}
+
+// PaneCreateWithOptions splits a pane and returns the ID of the new pane.
+func PaneCreateWithOptions(ctx context.Context, opts PaneOptions) (string, error) {
+	args := []string{"split-window", "-P", "-F", "#{pane_id}"}
+	switch opts.Direction {
+	case SplitHorizontal:
+		args = append(args, "-h")
+	case SplitVertical:
+		args = append(args, "-v")
+	}
+	if opts.Target != "" {
+		args = append(args, "-t", opts.Target)
+	}
+	if opts.Path != "" {
+		args = append(args, "-c", opts.Path)
+	}
+	args = append(args, envArgs(opts.Env)...)
+	if opts.Command != "" {
+		args = append(args, opts.Command)
+	}
+
+	cmd := command(ctx, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if err := handleTmuxError(string(output)); err != nil {
+			return "", err
+		}
+
+		return "", errors.WrapIff(err, "failed to create pane: %s", output)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// WindowCreateWithOptions opens a new window and returns its ID and the ID of its pane.
+func WindowCreateWithOptions(ctx context.Context, opts WindowOptions) (string, string, error) {
+	args := []string{"new-window", "-P", "-F", "#{window_id}|#{pane_id}"}
+	if opts.Detached {
+		args = append(args, "-d")
+	}
+	if opts.Target != "" {
+		args = append(args, "-t", opts.Target)
+	}
+	if opts.Name != "" {
+		args = append(args, "-n", opts.Name)
+	}
+	if opts.Path != "" {
+		args = append(args, "-c", opts.Path)
+	}
+	args = append(args, envArgs(opts.Env)...)
+	if opts.Command != "" {
+		args = append(args, opts.Command)
+	}
+
+	cmd := command(ctx, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if err := handleTmuxError(string(output)); err != nil {
+			return "", "", err
+		}
+
+		return "", "", errors.WrapIff(err, "failed to create window: %s", output)
+	}
+
+	windowID, paneID, _ := strings.Cut(strings.TrimSpace(string(output)), "|")
+
+	return windowID, paneID, nil
+}
+
+// WindowLink links the source window into the target session, right after its current window,
+// so the window shows in both sessions.
+func WindowLink(ctx context.Context, source, session string) error {
+	if err := run(ctx, "link-window", "-a", "-s", source, "-t", session+":"); err != nil {
+		return errors.WrapIff(err, "failed to link window %s into %s", source, session)
+	}
+
+	return nil
+}
+
+// PaneJoin moves the source pane into the target window, splitting its active pane.
+func PaneJoin(ctx context.Context, source, window string) error {
+	if err := run(ctx, "join-pane", "-s", source, "-t", window); err != nil {
+		return errors.WrapIff(err, "failed to join pane %s into %s", source, window)
+	}
+
+	return nil
+}
+
+// WindowKill closes the target window and every pane in it.
+func WindowKill(ctx context.Context, target string) error {
+	return run(ctx, "kill-window", "-t", target)
+}
+
+// WindowLayout applies a preset (e.g. main-vertical, tiled) or custom layout to the window.
+func WindowLayout(ctx context.Context, target, layout string) error {
+	return run(ctx, "select-layout", "-t", target, layout)
+}
+
+// WindowsRenumber closes gaps in the window indexes of the session.
+func WindowsRenumber(ctx context.Context, session string) error {
+	return run(ctx, "move-window", "-r", "-t", session+":")
+}
+
+// SendKeys types command into the target pane and presses enter, leaving the shell running after it.
+// The command is sent literally, words such as "Enter" or "C-c" in it are not looked up as keys.
+func SendKeys(ctx context.Context, target, command string) error {
+	if err := run(ctx, "send-keys", "-l", "-t", target, command); err != nil {
+		return err
+	}
+
+	return run(ctx, "send-keys", "-t", target, "Enter")
+}
+
+// SessionSetEnvironment sets a variable inherited by panes created afterwards in the session.
+func SessionSetEnvironment(ctx context.Context, session, key, value string) error {
+	return run(ctx, "set-environment", "-t", session, key, value)
+}
+
+func envArgs(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	args := make([]string, 0, len(env)*2)
+	for _, k := range keys {
+		args = append(args, "-e", k+"="+env[k])
+	}
+
+	return args
+}
+
+// PanePath returns the working directory of the target's active pane.
+func PanePath(ctx context.Context, target string) (string, error) {
+	output, err := command(ctx, "display-message", "-p", "-t", target, "#{pane_current_path}").CombinedOutput()
+	if err != nil {
+		if err := handleTmuxError(string(output)); err != nil {
+			return "", err
+		}
+
+		return "", errors.WrapIff(err, "failed to get path of: %s", target)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// PaneCapture returns the visible content of the target's active pane, including ANSI escapes.
+func PaneCapture(ctx context.Context, target string) (string, error) {
+	cmd := command(
 		ctx,
-		"tmux",
-		"new-window",
-		"-c", path, // start directory
+		"capture-pane",
+		"-e",         // keep escape sequences
+		"-p",         // print to stdout
+		"-t", target, // session, window or pane
 	)
 
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if err := handleTmuxError(string(output)); err != nil {
+			return "", err
+		}
+
+		return "", errors.WrapIff(err, "failed to capture pane: %s", output)
+	}
+
+	return string(output), nil
+}
+
+// run executes a tmux command whose output is only interesting on failure.
+func run(ctx context.Context, args ...string) error {
+	cmd := command(ctx, args...)
+
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		if err := handleTmuxError(string(output)); err != nil {
 			return err
 		}
-		return errors.WrapIff(err, "failed to create window: %s", output)
+
+		return errors.WrapIff(err, "tmux %s: %s", args[0], output)
 	}
 
 	return nil
 }
 
 func BuildSessionNameFromPath(path string) string {
-	base := filepath.Base(path)
+	return SanitizeSessionName(filepath.Base(path))
+}
 
-	replacer := strings.NewReplacer(
-		" ", "_",
-		".", "_",
-	)
+// SanitizeSessionName replaces everything tmux rejects or misreads in a target: the ':' and
+// '.' separators, whitespace and control characters, and leading characters that make the
+// name look like an ID ($ @ %), an exact match (=) or a special token (! ~ + - { ).
+func SanitizeSessionName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == ':' || r == '.' || r == utf8.RuneError || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return '_'
+		}
 
-	return replacer.Replace(base)
+		return r
+	}, strings.ToValidUTF8(name, "_"))
+
+	if name != "" && strings.ContainsRune("$@%=!~+-{", rune(name[0])) {
+		name = "_" + name[1:]
+	}
+
+	return name
 }
 
 func parseSession(line string) (*Session, error) {
-	parts := strings.SplitN(line, "|", 3)
-	if len(parts) < 3 {
+	parts := splitFormat(line)
+	if len(parts) != 3 {
 		return nil, errors.New("unexpected output from list-sessions")
 	}
 
@@ -244,3 +808,60 @@ func parseSession(line string) (*Session, error) {
 		Path: util.TruncateHomePath(strings.TrimSpace(parts[2])),
 	}, nil
 }
+
+func parseWindow(line string) (*Window, error) {
+	parts := splitFormat(line)
+	if len(parts) != 6 {
+		return nil, errors.New("unexpected output from list-windows")
+	}
+
+	return &Window{
+		ID:          strings.TrimSpace(parts[0]),
+		Name:        strings.TrimSpace(parts[1]),
+		SessionID:   strings.TrimSpace(parts[2]),
+		SessionName: strings.TrimSpace(parts[3]),
+		Index:       strings.TrimSpace(parts[4]),
+		Path:        util.TruncateHomePath(strings.TrimSpace(parts[5])),
+	}, nil
+}
+
+func parsePane(line string) (*Pane, error) {
+	parts := splitFormat(line)
+	if len(parts) != 6 {
+		return nil, errors.New("unexpected output from list-panes")
+	}
+
+	return &Pane{
+		ID:          strings.TrimSpace(parts[0]),
+		Command:     strings.TrimSpace(parts[1]),
+		SessionID:   strings.TrimSpace(parts[2]),
+		SessionName: strings.TrimSpace(parts[3]),
+		Index:       strings.TrimSpace(parts[4]),
+		Path:        util.TruncateHomePath(strings.TrimSpace(parts[5])),
+	}, nil
+}
+
+// splitFormat splits a line printed with one of the formats above on the separators that are
+// not escaped, and drops the escaping backslashes of the q: modifier.
+func splitFormat(line string) []string {
+	var parts []string
+	var field strings.Builder
+
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			field.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '|':
+			parts = append(parts, field.String())
+			field.Reset()
+		default:
+			field.WriteRune(r)
+		}
+	}
+
+	return append(parts, field.String())
+}
@@ -0,0 +1,115 @@
+package workspace
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/pkg/glob"
+	"tmux-session-launcher/pkg/logger"
+)
+
+// filter decides which subdirectories of a configured root are walked and listed.
+// Paths given to it are relative to root.
+type filter struct {
+	root      string
+	exclude   []*glob.Pattern
+	include   []*glob.Pattern
+	gitignore bool
+}
+
+func newFilter(root string, cfg *config.Config, dir config.DirectoryConfig) *filter {
+	return &filter{
+		root:      root,
+		exclude:   compilePatterns(slices.Concat(cfg.Exclude, dir.Exclude)),
+		include:   compilePatterns(slices.Concat(cfg.Include, dir.Include)),
+		gitignore: cfg.Gitignore || dir.Gitignore,
+	}
+}
+
+// compilePatterns drops invalid patterns, `config validate` reports them
+func compilePatterns(patterns []string) []*glob.Pattern {
+	result := make([]*glob.Pattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		p, err := glob.Compile(pattern)
+		if err != nil {
+			logger.Warnf("Ignoring pattern: %v", err)
+			continue
+		}
+
+		result = append(result, p)
+	}
+
+	return result
+}
+
+// excluded reports whether the directory should be neither listed nor descended into
+func (f *filter) excluded(fullPath string) bool {
+	return glob.MatchAny(f.exclude, f.rel(fullPath))
+}
+
+// included reports whether the directory should be listed, which is always the case without include patterns
+func (f *filter) included(fullPath string) bool {
+	return len(f.include) == 0 || glob.MatchAny(f.include, f.rel(fullPath))
+}
+
+// enter returns the filter to use below dir, extended with its .gitignore if enabled
+func (f *filter) enter(dir string) *filter {
+	if !f.gitignore {
+		return f
+	}
+
+	patterns := readGitignore(dir, f.rel(dir))
+	if len(patterns) == 0 {
+		return f
+	}
+
+	child := *f
+	child.exclude = append(append([]*glob.Pattern{}, f.exclude...), patterns...)
+
+	return &child
+}
+
+func (f *filter) rel(fullPath string) string {
+	rel, err := filepath.Rel(f.root, fullPath)
+	if err != nil {
+		return fullPath
+	}
+
+	return rel
+}
+
+// readGitignore turns the plain entries of dir/.gitignore into patterns relative to the walk root.
+// Negations are not supported and skipped.
+func readGitignore(dir string, rel string) []*glob.Pattern {
+	file, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var patterns []*glob.Pattern
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		// anchored entries are relative to the directory holding the .gitignore
+		if strings.Contains(strings.TrimSuffix(line, "/"), "/") && rel != "." {
+			line = filepath.ToSlash(rel) + "/" + strings.TrimPrefix(line, "/")
+		}
+
+		p, err := glob.Compile(line)
+		if err != nil {
+			continue
+		}
+
+		patterns = append(patterns, p)
+	}
+
+	return patterns
+}
@@ -0,0 +1,72 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"tmux-session-launcher/internal/config"
+)
+
+func TestFilterGitignore(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "api")
+	if err := os.MkdirAll(api, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(root, ".gitignore"), "# build output\ndist/\n!keep\n/tmp\n")
+	writeFile(t, filepath.Join(api, ".gitignore"), "generated/out\n")
+
+	f := newFilter(root, &config.Config{Gitignore: true}, config.DirectoryConfig{})
+	below := f.enter(root).enter(api)
+
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{"dist", true},
+		{"api/dist", true},
+		{"tmp", true},
+		{"api/tmp", false},
+		// anchored entries are relative to the directory of their .gitignore
+		{"api/generated/out", true},
+		{"generated/out", false},
+		// negations are not supported, the entry is skipped
+		{"keep", false},
+		{"api", false},
+	}
+
+	for _, tt := range tests {
+		if got := below.excluded(filepath.Join(root, tt.rel)); got != tt.want {
+			t.Errorf("excluded(%q) = %v, want %v", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestFilterWithoutGitignore(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".gitignore"), "dist\n")
+
+	f := newFilter(root, &config.Config{Exclude: []string{"node_modules"}}, config.DirectoryConfig{Include: []string{"src/*"}})
+	f = f.enter(root)
+
+	if f.excluded(filepath.Join(root, "dist")) {
+		t.Error("dist excluded although gitignore is off")
+	}
+
+	if !f.excluded(filepath.Join(root, "web", "node_modules")) {
+		t.Error("node_modules not excluded")
+	}
+
+	if !f.included(filepath.Join(root, "src", "api")) || f.included(filepath.Join(root, "lib", "api")) {
+		t.Error("include patterns not applied")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
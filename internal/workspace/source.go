@@ -0,0 +1,163 @@
+package workspace
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/pkg/util"
+
+	"emperror.dev/errors"
+)
+
+// ConfigSource tags the directories of the configuration file
+const ConfigSource = "config"
+
+// Source lists directories to open from one place
+type Source interface {
+	Name() string
+	Directories(ctx context.Context) ([]Directory, error)
+}
+
+// newSources returns the configured directories followed by the external sources
+func newSources(cfg *config.Config) []Source {
+	sources := []Source{configSource{cfg: cfg}}
+
+	for _, src := range cfg.Sources {
+		switch src.Type {
+		case config.SourceZoxide:
+			sources = append(sources, zoxideSource{name: src.SourceName(), weight: src.SourceWeight()})
+		case config.SourceFile:
+			sources = append(sources, fileSource{
+				name:   src.SourceName(),
+				path:   config.ExpandPath(src.Path),
+				weight: src.SourceWeight(),
+			})
+		}
+	}
+
+	return sources
+}
+
+type configSource struct {
+	cfg *config.Config
+}
+
+func (s configSource) Name() string {
+	return ConfigSource
+}
+
+func (s configSource) Directories(ctx context.Context) ([]Directory, error) {
+	return configDirectories(s.cfg), nil
+}
+
+// zoxideSource reads `zoxide query --list --score`. The scores are unbounded, so they are scaled
+// to 0..1 by the best one before weighting, otherwise zoxide would drown the launcher's history.
+type zoxideSource struct {
+	name   string
+	weight float64
+}
+
+func (s zoxideSource) Name() string {
+	return s.name
+}
+
+func (s zoxideSource) Directories(ctx context.Context) ([]Directory, error) {
+	output, err := exec.CommandContext(ctx, "zoxide", "query", "--list", "--score").Output()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to query zoxide")
+	}
+
+	var dirs []Directory
+	var best float64
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		// "  12.5 /path/with maybe spaces"
+		scoreText, path, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !ok {
+			continue
+		}
+
+		score, err := strconv.ParseFloat(scoreText, 64)
+		if err != nil {
+			continue
+		}
+
+		path = strings.TrimSpace(path)
+		if !isDir(path) {
+			// zoxide only forgets removed directories lazily
+			continue
+		}
+
+		best = max(best, score)
+		dirs = append(dirs, newExternalDirectory(path, s.name, score))
+	}
+
+	for i := range dirs {
+		if best > 0 {
+			dirs[i].Score = dirs[i].Score / best * s.weight
+		}
+	}
+
+	return dirs, scanner.Err()
+}
+
+// fileSource reads a file listing one directory per line, blank lines and # comments are skipped
+type fileSource struct {
+	name   string
+	path   string
+	weight float64
+}
+
+func (s fileSource) Name() string {
+	return s.name
+}
+
+func (s fileSource) Directories(ctx context.Context) ([]Directory, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, errors.WrapIff(err, "failed to open: %s", s.path)
+	}
+	defer file.Close()
+
+	var dirs []Directory
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		path := config.ExpandPath(line)
+		if !isDir(path) {
+			continue
+		}
+
+		dirs = append(dirs, newExternalDirectory(path, s.name, s.weight))
+	}
+
+	return dirs, scanner.Err()
+}
+
+func newExternalDirectory(path string, source string, score float64) Directory {
+	return Directory{
+		FullPath:          path,
+		TruncatedHomePath: util.TruncateHomePath(path),
+		Parent:            filepath.Base(filepath.Dir(path)),
+		Label:             filepath.Base(path),
+		Source:            source,
+		Score:             score,
+	}
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -1,6 +1,7 @@
 package workspace
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,10 +15,14 @@ type Directory struct {
 	TruncatedHomePath string
 	Parent            string
 	Label             string
+	// Source names where the directory came from, Score is the source's own ranking of it
+	Source string
+	Score  float64
 }
 
-// GetDirectories loads directory configuration from YAML file and returns all directories
-func GetDirectories() []Directory {
+// GetDirectories loads directory configuration from YAML file and returns the directories of
+// every source, the configured ones first
+func GetDirectories(ctx context.Context) []Directory {
 	var allDirs []Directory
 
 	// Load configuration from YAML file
@@ -27,9 +32,27 @@ func GetDirectories() []Directory {
 		return allDirs
 	}
 
+	for _, src := range newSources(cfg) {
+		dirs, err := src.Directories(ctx)
+		if err != nil {
+			logger.Warnf("Failed to list directories of %s: %v", src.Name(), err)
+			continue
+		}
+
+		allDirs = append(allDirs, dirs...)
+	}
+
+	// Deduplicate and return
+	return deduplicateDirectories(allDirs)
+}
+
+// configDirectories walks the directories of the configuration file
+func configDirectories(cfg *config.Config) []Directory {
+	var allDirs []Directory
+
 	// Collect all directories first
 	for _, dir := range cfg.Directories {
-		expandedPath := os.ExpandEnv(dir.Path)
+		expandedPath := config.ExpandPath(dir.Path)
 		base := filepath.Base(expandedPath)
 		truncatedHome := util.TruncateHomePath(expandedPath)
 
@@ -38,18 +61,23 @@ func GetDirectories() []Directory {
 			Parent:            base,
 			Label:             base,
 			TruncatedHomePath: truncatedHome,
+			Source:            ConfigSource,
 		})
 
-		if dir.Depth > 0 {
-			allDirs = append(allDirs, getSubDirectories(expandedPath, base, dir.Depth)...)
+		f := newFilter(expandedPath, cfg, dir).enter(expandedPath)
+
+		if dir.Discover == config.DiscoverGit {
+			markers := append([]string{".git"}, dir.Markers...)
+			allDirs = append(allDirs, discoverProjects(expandedPath, base, dir.DiscoverDepth(), markers, f)...)
+		} else if dir.Depth > 0 {
+			allDirs = append(allDirs, getSubDirectories(expandedPath, base, dir.Depth, f)...)
 		}
 	}
 
-	// Deduplicate and return
-	return deduplicateDirectories(allDirs)
+	return allDirs
 }
 
-func getSubDirectories(basePath string, baseLabel string, depth int) []Directory {
+func getSubDirectories(basePath string, baseLabel string, depth int, f *filter) []Directory {
 	var result []Directory
 
 	entries, err := os.ReadDir(basePath)
@@ -60,18 +88,25 @@ func getSubDirectories(basePath string, baseLabel string, depth int) []Directory
 	for _, entry := range entries {
 		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
 			fullPath := filepath.Join(basePath, entry.Name())
+			if f.excluded(fullPath) {
+				continue
+			}
+
 			baseLabel := filepath.Join(baseLabel, entry.Name())
 			truncatedHome := util.TruncateHomePath(fullPath)
 
-			result = append(result, Directory{
-				FullPath:          fullPath,
-				Parent:            baseLabel,
-				Label:             entry.Name(),
-				TruncatedHomePath: truncatedHome,
-			})
+			if f.included(fullPath) {
+				result = append(result, Directory{
+					FullPath:          fullPath,
+					Parent:            baseLabel,
+					Label:             entry.Name(),
+					TruncatedHomePath: truncatedHome,
+					Source:            ConfigSource,
+				})
+			}
 
 			if depth > 1 {
-				result = append(result, getSubDirectories(fullPath, baseLabel, depth-1)...)
+				result = append(result, getSubDirectories(fullPath, baseLabel, depth-1, f.enter(fullPath))...)
 			}
 		}
 	}
@@ -79,16 +114,72 @@ func getSubDirectories(basePath string, baseLabel string, depth int) []Directory
 	return result
 }
 
-// deduplicateDirectories removes duplicate directories based on FullPath
+// discoverProjects walks down to depth looking for project roots, without descending into them
+func discoverProjects(basePath string, baseLabel string, depth int, markers []string, f *filter) []Directory {
+	var result []Directory
+
+	entries, err := os.ReadDir(basePath)
+	if err != nil {
+		return result
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		fullPath := filepath.Join(basePath, entry.Name())
+		if f.excluded(fullPath) {
+			continue
+		}
+
+		baseLabel := filepath.Join(baseLabel, entry.Name())
+
+		if isProject(fullPath, markers) {
+			if f.included(fullPath) {
+				result = append(result, Directory{
+					FullPath:          fullPath,
+					Parent:            baseLabel,
+					Label:             entry.Name(),
+					TruncatedHomePath: util.TruncateHomePath(fullPath),
+					Source:            ConfigSource,
+				})
+			}
+			continue
+		}
+
+		if depth > 1 {
+			result = append(result, discoverProjects(fullPath, baseLabel, depth-1, markers, f.enter(fullPath))...)
+		}
+	}
+
+	return result
+}
+
+func isProject(path string, markers []string) bool {
+	for _, marker := range markers {
+		if _, err := os.Stat(filepath.Join(path, marker)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deduplicateDirectories removes duplicate directories based on FullPath, the first one is kept
+// and collects the scores of the others
 func deduplicateDirectories(dirs []Directory) []Directory {
-	seen := make(map[string]struct{})
+	seen := make(map[string]int)
 	var result []Directory
 
 	for _, dir := range dirs {
-		if _, exists := seen[dir.FullPath]; !exists {
-			seen[dir.FullPath] = struct{}{}
-			result = append(result, dir)
+		if i, exists := seen[dir.FullPath]; exists {
+			result[i].Score += dir.Score
+			continue
 		}
+
+		seen[dir.FullPath] = len(result)
+		result = append(result, dir)
 	}
 
 	return result
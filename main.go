@@ -8,7 +8,15 @@ import (
 	"time"
 	"tmux-session-launcher/internal/action"
 	"tmux-session-launcher/internal/config"
+	"tmux-session-launcher/internal/fuzzyfinder"
+	"tmux-session-launcher/internal/history"
 	"tmux-session-launcher/internal/launcher"
+	"tmux-session-launcher/internal/list"
+	"tmux-session-launcher/internal/mode"
+	"tmux-session-launcher/internal/popup"
+	"tmux-session-launcher/internal/provider"
+	"tmux-session-launcher/internal/rpc"
+	"tmux-session-launcher/internal/tmux"
 	"tmux-session-launcher/pkg/logger"
 
 	"github.com/urfave/cli/v3"
@@ -30,15 +38,73 @@ func main() {
 					return logger.SetVerbosity(int(value))
 				},
 			},
+			&cli.StringFlag{
+				Name:    flagTmuxServer,
+				Aliases: []string{"L"},
+				Usage:   "Socket name of the tmux server to use, as with tmux -L",
+			},
+			&cli.StringFlag{
+				Name:    flagTmuxSocket,
+				Aliases: []string{"S"},
+				Usage:   "Socket path of the tmux server to use, as with tmux -S",
+			},
+		},
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			cfg := existingConfig()
+			tmux.SetDefaultServer(tmuxServer(cmd, cfg))
+			fuzzyfinder.TrackHistory()
+			provider.RegisterModes(cfg)
+			return ctx, nil
 		},
 
 		Commands: []*cli.Command{
 			{
 				Name:   "launch",
-				Action: WithSignalHandling(launcher.HandlerLauncer),
+				Action: WithSignalHandling(popup.WithPopup(launcher.HandlerLauncer)),
+				Flags:  []cli.Flag{popupFlag()},
+			},
+			{
+				Name:      "switch",
+				Aliases:   []string{"s"},
+				Usage:     "Open the entry matching the query, or the picker when several do",
+				ArgsUsage: "<query>",
+				Action:    WithSignalHandling(popup.WithPopup(launcher.HandlerSwitch)),
+				Flags:     []cli.Flag{popupFlag()},
+			},
+			{
+				Name:   "last",
+				Usage:  "Switch to the most recently used session that still exists",
+				Action: launcher.HandlerLast,
+			},
+			{
+				Name:   "list",
+				Usage:  "Print the launcher entries without starting the picker",
+				Action: list.HandlerList,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "mode",
+						Aliases: []string{"m"},
+						Value:   mode.ModeAll.String(),
+						Usage:   "Entries to list: all, session, directory, window or pane",
+					},
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Value:   list.FormatTable,
+						Usage:   "Output format: table, json or tsv",
+					},
+				},
 			},
 			{
 				Name: "action",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     action.FlagSocket,
+						Usage:    "Path to the RPC socket of the running launcher",
+						Sources:  cli.EnvVars(rpc.EnvSockAddress),
+						Required: true,
+					},
+				},
 				Commands: []*cli.Command{
 					{
 						Name:    "mode-next",
@@ -58,10 +124,28 @@ func main() {
 						Name:   "content-get",
 						Action: WithSignalHandling(action.HandlerGetContent),
 					},
+					{
+						Name:   "preview",
+						Action: WithSignalHandling(action.HandlerPreview),
+					},
 					{
 						Name:   "open-in",
 						Action: WithSignalHandling(action.HandlerOpenIn),
 					},
+					{
+						Name:   "rename",
+						Action: WithSignalHandling(action.HandlerRename),
+					},
+					{
+						Name:   "kill",
+						Action: WithSignalHandling(action.HandlerKill),
+					},
+					{
+						Name:      "run",
+						Usage:     "Run a custom action from the configuration",
+						ArgsUsage: "<name> <selection>",
+						Action:    WithSignalHandling(action.HandlerRunAction),
+					},
 				},
 			},
 			{
@@ -115,6 +199,36 @@ func main() {
 					},
 				},
 			},
+			{
+				Name:  "history",
+				Usage: "Manage the usage history used to rank entries",
+				Commands: []*cli.Command{
+					{
+						Name:    "show",
+						Aliases: []string{"ls"},
+						Usage:   "Show recorded entries ranked by frecency",
+						Action:  history.HandlerShowHistory,
+					},
+					{
+						Name:   "prune",
+						Usage:  "Remove entries not used recently",
+						Action: history.HandlerPruneHistory,
+						Flags: []cli.Flag{
+							&cli.IntFlag{
+								Name:    "days",
+								Aliases: []string{"d"},
+								Value:   90,
+								Usage:   "Remove entries not used within this many days",
+							},
+						},
+					},
+					{
+						Name:   "clear",
+						Usage:  "Remove all recorded entries",
+						Action: history.HandlerClearHistory,
+					},
+				},
+			},
 		},
 
 		DefaultCommand: "launch",
@@ -125,6 +239,52 @@ func main() {
 	}
 }
 
+const (
+	flagTmuxServer = "tmux-server"
+	flagTmuxSocket = "tmux-socket"
+)
+
+// existingConfig returns the configuration for the global setup, or an empty one when there is
+// none yet or it does not load.
+func existingConfig() *config.Config {
+	// loading would create a missing file, which config init must do itself
+	if _, err := os.Stat(config.GetConfigPath()); err != nil {
+		return &config.Config{}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		// reported by the commands that need the configuration
+		return &config.Config{}
+	}
+
+	return cfg
+}
+
+// tmuxServer picks the tmux server from the flags, falling back to the configuration.
+func tmuxServer(cmd *cli.Command, cfg *config.Config) tmux.Server {
+	if socket := cmd.String(flagTmuxSocket); socket != "" {
+		return tmux.Server{Socket: socket}
+	}
+
+	if name := cmd.String(flagTmuxServer); name != "" {
+		return tmux.Server{Name: name}
+	}
+
+	if cfg.Tmux.Socket != "" {
+		return tmux.Server{Socket: config.ExpandPath(cfg.Tmux.Socket)}
+	}
+
+	return tmux.Server{Name: cfg.Tmux.Server}
+}
+
+func popupFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  popup.FlagPopup,
+		Usage: "Run inside a tmux popup, sized by the popup section of the configuration",
+	}
+}
+
 // WithSignalHandling wraps a CLI action with graceful shutdown signal handling.
 func WithSignalHandling(next cli.ActionFunc) cli.ActionFunc {
 	return func(ctx context.Context, cmd *cli.Command) error {
@@ -0,0 +1,99 @@
+package glob
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var ErrEmptyPattern = errors.New("empty pattern")
+
+// Pattern is a gitignore-like glob. Patterns without a slash match the base name at any
+// depth, the others match the slash separated path relative to the walk root, where
+// "**" stands for any number of path segments.
+type Pattern struct {
+	raw      string
+	segments []string
+	basename bool
+}
+
+func Compile(pattern string) (*Pattern, error) {
+	p := strings.TrimSuffix(strings.TrimSpace(pattern), "/")
+	if p == "" {
+		return nil, ErrEmptyPattern
+	}
+
+	anchored := strings.Contains(p, "/")
+	segments := strings.Split(strings.TrimPrefix(p, "/"), "/")
+
+	for _, seg := range segments {
+		if seg == "**" {
+			continue
+		}
+
+		if _, err := path.Match(seg, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	return &Pattern{
+		raw:      pattern,
+		segments: segments,
+		basename: !anchored,
+	}, nil
+}
+
+// Match reports whether rel, a path relative to the walk root, matches the pattern.
+func (p *Pattern) Match(rel string) bool {
+	rel = filepath.ToSlash(rel)
+
+	if p.basename {
+		ok, _ := path.Match(p.segments[0], path.Base(rel))
+		return ok
+	}
+
+	return matchSegments(p.segments, strings.Split(rel, "/"))
+}
+
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+// MatchAny reports whether rel matches at least one of the patterns.
+func MatchAny(patterns []*Pattern, rel string) bool {
+	for _, p := range patterns {
+		if p.Match(rel) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
@@ -0,0 +1,124 @@
+package glob
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr error
+		invalid bool
+	}{
+		{pattern: "node_modules"},
+		{pattern: "src/**/build/"},
+		{pattern: "", wantErr: ErrEmptyPattern},
+		{pattern: "  ", wantErr: ErrEmptyPattern},
+		{pattern: "/", wantErr: ErrEmptyPattern},
+		{pattern: "[", invalid: true},
+		{pattern: "src/[a-", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, err := Compile(tt.pattern)
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Compile(%q) error = %v, want %v", tt.pattern, err, tt.wantErr)
+				}
+			case tt.invalid:
+				if err == nil {
+					t.Errorf("Compile(%q) succeeded, want an error", tt.pattern)
+				}
+			case err != nil:
+				t.Errorf("Compile(%q) error = %v", tt.pattern, err)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		// without a slash the base name matches at any depth
+		{"node_modules", "node_modules", true},
+		{"node_modules", "web/node_modules", true},
+		{"node_modules", "a/b/node_modules", true},
+		{"node_modules", "node_modules2", false},
+		{"*.tmp", "cache/x.tmp", true},
+		{"*.tmp", "x.tmp/child", false},
+
+		// a trailing slash alone does not anchor
+		{"build/", "build", true},
+		{"build/", "api/build", true},
+
+		// a leading slash anchors to the walk root
+		{"/build", "build", true},
+		{"/build", "api/build", false},
+
+		// a slash anywhere else anchors too, one segment per segment
+		{"src/*", "src/api", true},
+		{"src/*", "src/api/v1", false},
+		{"src/*", "lib/src/api", false},
+
+		// ** stands for any number of segments, none included
+		{"**/vendor", "vendor", true},
+		{"**/vendor", "a/b/vendor", true},
+		{"**/vendor", "vendor/a", false},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/x/c", false},
+		{"src/**", "src/a/b", true},
+		{"src/**", "lib/a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.rel, func(t *testing.T) {
+			p, err := Compile(tt.pattern)
+			if err != nil {
+				t.Fatalf("Compile(%q) error = %v", tt.pattern, err)
+			}
+
+			if got := p.Match(tt.rel); got != tt.want {
+				t.Errorf("%q.Match(%q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	var patterns []*Pattern
+	for _, raw := range []string{"node_modules", "/tmp"} {
+		p, err := Compile(raw)
+		if err != nil {
+			t.Fatalf("Compile(%q) error = %v", raw, err)
+		}
+		patterns = append(patterns, p)
+	}
+
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{"web/node_modules", true},
+		{"tmp", true},
+		{"web/tmp", false},
+		{"web", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchAny(patterns, tt.rel); got != tt.want {
+			t.Errorf("MatchAny(%q) = %v, want %v", tt.rel, got, tt.want)
+		}
+	}
+
+	if MatchAny(nil, "anything") {
+		t.Error("MatchAny without patterns matched")
+	}
+}
@@ -0,0 +1,69 @@
+package style
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var attributes = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"faint":     color.Faint,
+	"dim":       color.Faint,
+	"italic":    color.Italic,
+	"underline": color.Underline,
+	"reverse":   color.ReverseVideo,
+
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+
+	"hi-black":   color.FgHiBlack,
+	"hi-red":     color.FgHiRed,
+	"hi-green":   color.FgHiGreen,
+	"hi-yellow":  color.FgHiYellow,
+	"hi-blue":    color.FgHiBlue,
+	"hi-magenta": color.FgHiMagenta,
+	"hi-cyan":    color.FgHiCyan,
+	"hi-white":   color.FgHiWhite,
+}
+
+// Parse reads a space separated style such as "hi-cyan italic", "#808080" or "244 bold". Colors
+// are ANSI names (optionally prefixed with hi-), 256-color numbers or #rrggbb.
+func Parse(spec string) (*color.Color, error) {
+	c := color.New()
+
+	for word := range strings.FieldsSeq(strings.ToLower(spec)) {
+		if attr, ok := attributes[word]; ok {
+			c.Add(attr)
+			continue
+		}
+
+		if hex, ok := strings.CutPrefix(word, "#"); ok && len(hex) == 6 {
+			rgb, err := strconv.ParseUint(hex, 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid color %q", word)
+			}
+
+			c.AddRGB(int(rgb>>16), int(rgb>>8&0xff), int(rgb&0xff))
+			continue
+		}
+
+		if n, err := strconv.Atoi(word); err == nil && n >= 0 && n <= 255 {
+			// 256-color foreground
+			c.Add(38, 5, color.Attribute(n))
+			continue
+		}
+
+		return nil, fmt.Errorf("unknown color or attribute %q", word)
+	}
+
+	return c, nil
+}
@@ -8,3 +8,12 @@ import (
 func TruncateHomePath(path string) string {
 	return strings.Replace(path, os.ExpandEnv("$HOME"), "~", 1)
 }
+
+// ExpandHomePath reverses TruncateHomePath.
+func ExpandHomePath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		return os.ExpandEnv("$HOME") + path[1:]
+	}
+
+	return path
+}
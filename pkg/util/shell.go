@@ -0,0 +1,121 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// ShellQuote quotes s so a POSIX shell reads it back as a single word.
+func ShellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, needsQuoting) < 0 {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ShellJoin quotes every argument and joins them into a shell command line.
+func ShellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = ShellQuote(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// ShellTemplate renders a text/template into a command line for sh -c. Every field is
+// shell-quoted before it is substituted, the template must not quote the placeholders again.
+func ShellTemplate(name string, text string, fields map[string]string) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	quoted := make(map[string]string, len(fields))
+	for key, value := range fields {
+		quoted[key] = ShellQuote(value)
+	}
+
+	var command strings.Builder
+	if err := tmpl.Execute(&command, quoted); err != nil {
+		return "", err
+	}
+
+	return command.String(), nil
+}
+
+func needsQuoting(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	}
+
+	return !strings.ContainsRune("-_./=:,+@%", r)
+}
+
+// ShellSplit splits s into words the way a POSIX shell would, without any expansion.
+func ShellSplit(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			// inside double quotes a backslash only escapes a few characters
+			if quote == '"' && !strings.ContainsRune("\"\\$`", r) {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}